@@ -12,7 +12,6 @@ require (
 	github.com/gonvenience/text v1.0.9
 	github.com/gonvenience/ytbx v1.4.7
 	github.com/lucasb-eyer/go-colorful v1.2.0
-	github.com/mitchellh/hashstructure v1.1.0
 	github.com/onsi/ginkgo/v2 v2.23.4
 	github.com/onsi/gomega v1.37.0
 	github.com/spf13/cobra v1.9.1
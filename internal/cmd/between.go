@@ -101,14 +101,60 @@ types are: YAML (http://yaml.org/) and JSON (http://json.org/).
 			}
 		}
 
+		var identifierRules []dyff.IdentifierRule
+		for _, rule := range reportOptions.IdentifierRules {
+			identifierRule, err := dyff.ParseIdentifierRule(rule)
+			if err != nil {
+				return err
+			}
+
+			identifierRules = append(identifierRules, identifierRule)
+		}
+
+		var listOrderRules []dyff.ListOrderRule
+		for _, rule := range reportOptions.ListOrderRules {
+			listOrderRule, err := dyff.ParseListOrderRule(rule)
+			if err != nil {
+				return err
+			}
+
+			listOrderRules = append(listOrderRules, listOrderRule)
+		}
+
+		var schemas *dyff.Schemas
+		if len(reportOptions.Schemas) > 0 {
+			if schemas, err = dyff.LoadSchemas(reportOptions.Schemas...); err != nil {
+				return fmt.Errorf("failed to load schemas: %w", err)
+			}
+		}
+
 		report, err := dyff.CompareInputFiles(from, to,
 			dyff.IgnoreOrderChanges(reportOptions.IgnoreOrderChanges),
 			dyff.IgnoreWhitespaceChanges(reportOptions.IgnoreWhitespaceChanges),
 			dyff.KubernetesEntityDetection(reportOptions.KubernetesEntityDetection),
+			dyff.KubernetesQuantities(reportOptions.KubernetesEntityDetection),
 			dyff.AdditionalIdentifiers(reportOptions.AdditionalIdentifiers...),
+			dyff.IdentifierRules(identifierRules...),
+			dyff.KubernetesSchemas(schemas),
+			dyff.ListOrderRules(listOrderRules...),
 			dyff.DetectRenames(reportOptions.DetectRenames),
+			dyff.DetectEntryRenames(reportOptions.DetectEntryRenames),
+			dyff.DetectKeyOrderChanges(reportOptions.DetectKeyOrderChanges),
+			dyff.DetectMapEntryMoves(reportOptions.DetectMapEntryMoves),
+			dyff.NullAsAbsent(reportOptions.NullAsAbsent),
+			dyff.EmptyAsAbsent(reportOptions.EmptyAsAbsent),
+			dyff.CompareComments(reportOptions.CompareComments),
+			dyff.ResolveMergeKeys(reportOptions.ResolveMergeKeys),
+			dyff.ReportAnchorChanges(reportOptions.ReportAnchorChanges),
+			dyff.NormalizeTags(reportOptions.NormalizeTags),
 			dyff.MarshalJsonStrings(reportOptions.MarshalJsonStrings),
 			dyff.ChompBlockScalars(reportOptions.ChompBlockScalars),
+			dyff.DetectEmbeddedDocuments(reportOptions.DetectEmbeddedDocuments),
+			dyff.SemanticScalars(reportOptions.SemanticScalars),
+			dyff.FloatTolerance(reportOptions.FloatTolerance),
+			dyff.PositionalLists(reportOptions.PositionalLists),
+			dyff.FuzzyListPairing(reportOptions.FuzzyListPairing),
+			dyff.Concurrency(reportOptions.Concurrency),
 		)
 
 		if err != nil {
@@ -296,6 +296,47 @@ list
 			Expect(out).To(BeEquivalentTo(fmt.Sprintf("one change detected between %s and %s\n\n", from, to)))
 		})
 
+		It("should create a JSON patch", func() {
+			from := createTestFile(`{"list":[{"name":"one","aaa":"bbb"},{"name":"two","aaa":"bbb"}],"foo":"bar"}`)
+			defer os.Remove(from)
+
+			to := createTestFile(`{"list":[{"name":"two","aaa":"ccc"},{"name":"one","aaa":"bbb"}]}`)
+			defer os.Remove(to)
+
+			out, err := dyff("between", "--output=json-patch", from, to)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(BeEquivalentTo(`[{"op":"remove","path":"/foo"},{"op":"replace","path":"/list/1/aaa","value":"ccc"},{"op":"move","path":"/list/0","from":"/list/1"}]
+`))
+		})
+
+		It("should create a merge patch", func() {
+			from := createTestFile(`{"list":[{"name":"one","aaa":"bbb"}],"foo":"bar","some":{"a":1,"b":2}}`)
+			defer os.Remove(from)
+
+			to := createTestFile(`{"list":[{"name":"one","aaa":"ccc"}],"some":{"a":1,"b":3}}`)
+			defer os.Remove(to)
+
+			out, err := dyff("between", "--output=merge-patch", from, to)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(BeEquivalentTo(`{"foo":null,"list":[{"aaa":"ccc","name":"one"}],"some":{"b":3}}
+`))
+		})
+
+		It("should create a go-patch operations file", func() {
+			from := createTestFile(`{"instance_groups":[{"name":"web","instances":1}]}`)
+			defer os.Remove(from)
+
+			to := createTestFile(`{"instance_groups":[{"name":"web","instances":2}]}`)
+			defer os.Remove(to)
+
+			out, err := dyff("between", "--output=go-patch", from, to)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(BeEquivalentTo(`- type: replace
+  path: /instance_groups/name=web/instances
+  value: 2
+`))
+		})
+
 		It("should create a report using a custom root in the files", func() {
 			from, to := assets("examples", "from.yml"), assets("examples", "to.yml")
 			expected := fmt.Sprintf(`     _        __  __
@@ -495,6 +536,28 @@ example_two
 			Expect(exitCode.Value()).To(Equal(1))
 		})
 
+		It("should create exit code zero if values are only equivalent", func() {
+			from := createTestFile(`{"a": 1.0, "b": 1.5}`)
+			defer os.Remove(from)
+
+			to := createTestFile(`{"a": 1, "b": 1.6}`)
+			defer os.Remove(to)
+
+			_, err := dyff("between", "--set-exit-code", "--semantic-scalars", "--float-tolerance=0.2", from, to)
+			Expect(err).To(HaveOccurred())
+
+			exitCode, ok := err.(ExitCode)
+			Expect(ok).To(BeTrue())
+			Expect(exitCode.Value()).To(Equal(0))
+
+			_, err = dyff("between", "--set-exit-code", "--semantic-scalars", from, to)
+			Expect(err).To(HaveOccurred())
+
+			exitCode, ok = err.(ExitCode)
+			Expect(ok).To(BeTrue())
+			Expect(exitCode.Value()).To(Equal(1))
+		})
+
 		It("should fail with an exit code other than zero or one in case of an error", func() {
 			_, err := dyff("between", "--set-exit-code", "from", "to")
 			Expect(err).To(HaveOccurred())
@@ -581,6 +644,250 @@ spec.replicas  (apps/v1/Deployment/test)
 			Expect(out).To(BeEquivalentTo("\n"))
 		})
 
+		It("should use the identifier rules for lists at matching paths", func() {
+			from := createTestFile(`{"ports":[{"containerPort":80,"protocol":"TCP","name":"http"},{"containerPort":80,"protocol":"UDP","name":"quic"}]}`)
+			defer os.Remove(from)
+
+			to := createTestFile(`{"ports":[{"containerPort":80,"protocol":"TCP","name":"web"},{"containerPort":80,"protocol":"UDP","name":"quic"}]}`)
+			defer os.Remove(to)
+
+			out, err := dyff("between", "--omit-header", "--identifier-rule", "ports=containerPort,protocol", from, to)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(BeEquivalentTo(`
+ports.containerPort=80,protocol=TCP.name
+  ± value change
+    - http
+    + web
+
+`))
+		})
+
+		It("should only detect renamed map keys if the respective flag is set", func() {
+			from := createTestFile(`{"databases": {"primary": {"host": "db-1", "port": 5432, "user": "admin"}}}`)
+			defer os.Remove(from)
+
+			to := createTestFile(`{"databases": {"main": {"host": "db-1", "port": 5433, "user": "admin"}}}`)
+			defer os.Remove(to)
+
+			out, err := dyff("between", "--omit-header", from, to)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(BeEquivalentTo(`
+databases
+  - one map entry removed:     + one map entry added:
+    primary:                     main:
+      host: db-1                   host: db-1
+      port: 5432                   port: 5433
+      user: admin                  user: admin
+
+`))
+
+			out, err = dyff("between", "--omit-header", "--detect-entry-renames", from, to)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(BeEquivalentTo(`
+databases.primary
+  → renamed
+    - primary
+    + main
+
+databases.primary.port
+  ± value change
+    - 5432
+    + 5433
+
+`))
+		})
+
+		It("should use the list semantics declared in the provided schemas", func() {
+			from := createTestFile(`{"apiVersion":"example.com/v1","kind":"Gateway","spec":{"hostnames":["foo.example.com","bar.example.com"]}}`)
+			defer os.Remove(from)
+
+			to := createTestFile(`{"apiVersion":"example.com/v1","kind":"Gateway","spec":{"hostnames":["bar.example.com","foo.example.com"]}}`)
+			defer os.Remove(to)
+
+			out, err := dyff("between", "--omit-header", "--schema", assets("schemas", "crd.yml"), from, to)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(BeEquivalentTo("\n"))
+		})
+
+		It("should use the list order rules to ignore order changes in specific lists", func() {
+			from := createTestFile(`{"args": ["--verbose", "--debug"], "hosts": ["foo", "bar"]}`)
+			defer os.Remove(from)
+
+			to := createTestFile(`{"args": ["--debug", "--verbose"], "hosts": ["bar", "foo"]}`)
+			defer os.Remove(to)
+
+			out, err := dyff("between", "--omit-header", "--list-order", "hosts=set", from, to)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(BeEquivalentTo(`
+args
+  ⇆ order changed
+    - --verbose, --debug
+    + --debug, --verbose
+
+`))
+		})
+
+		It("should report changes of the order of keys if enabled", func() {
+			from := createTestFile(`---
+metadata:
+  name: foo
+  namespace: bar
+  labels: {app: foo}
+`)
+			defer os.Remove(from)
+
+			to := createTestFile(`---
+metadata:
+  namespace: bar
+  name: foo
+  labels: {app: foo}
+`)
+			defer os.Remove(to)
+
+			out, err := dyff("between", "--omit-header", "--detect-key-order-changes", from, to)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(BeEquivalentTo(`
+metadata
+  ⇆ order changed
+    - name, namespace, labels
+    + namespace, name, labels
+
+`))
+
+			out, err = dyff("between", "--omit-header", from, to)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(BeEquivalentTo("\n"))
+		})
+
+		It("should report subtrees that were moved if enabled", func() {
+			from := createTestFile(`---
+spec:
+  securityContext:
+    runAsNonRoot: true
+    runAsUser: 1000
+  template:
+    spec:
+      containers: []
+`)
+			defer os.Remove(from)
+
+			to := createTestFile(`---
+spec:
+  template:
+    spec:
+      securityContext:
+        runAsNonRoot: true
+        runAsUser: 1001
+      containers: []
+`)
+			defer os.Remove(to)
+
+			out, err := dyff("between", "--omit-header", "--detect-map-entry-moves", from, to)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(BeEquivalentTo(`
+spec.template.spec.securityContext
+  ⇢ moved from spec.securityContext to spec.template.spec.securityContext
+
+spec.template.spec.securityContext.runAsUser
+  ± value change
+    - 1000
+    + 1001
+
+`))
+		})
+
+		It("should treat null and empty values like absent values if enabled", func() {
+			from := createTestFile(`{"metadata": {"name": "foo", "labels": null, "annotations": {}}}`)
+			defer os.Remove(from)
+
+			to := createTestFile(`{"metadata": {"name": "foo", "finalizers": []}}`)
+			defer os.Remove(to)
+
+			out, err := dyff("between", "--omit-header", "--null-as-absent", "--empty-as-absent", from, to)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(BeEquivalentTo("\n"))
+		})
+
+		It("should report changes of comments if enabled", func() {
+			from := createTestFile(`---
+image:
+  # deprecated: use image.repository
+  name: nginx
+`)
+			defer os.Remove(from)
+
+			to := createTestFile(`---
+image:
+  # removed in the next release
+  name: nginx
+`)
+			defer os.Remove(to)
+
+			out, err := dyff("between", "--omit-header", "--compare-comments", from, to)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(BeEquivalentTo(`
+image.name
+  # head comment changed
+    - # deprecated: use image.repository
+    + # removed in the next release
+
+`))
+		})
+
+		It("should resolve merge keys and report anchor changes if enabled", func() {
+			from := createTestFile(`---
+defaults: &defaults
+  adapter: postgres
+development:
+  adapter: postgres
+  database: dev
+`)
+			defer os.Remove(from)
+
+			to := createTestFile(`---
+defaults: &defaults
+  adapter: postgres
+development:
+  <<: *defaults
+  database: dev
+`)
+			defer os.Remove(to)
+
+			out, err := dyff("between", "--omit-header", "--resolve-merge-keys", from, to)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(BeEquivalentTo("\n"))
+
+			out, err = dyff("between", "--omit-header", "--resolve-merge-keys", "--report-anchor-changes", from, to)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(BeEquivalentTo(`
+development
+  & anchor/alias change
+    + merge *defaults
+
+`))
+		})
+
+		It("should report the same differences when documents are compared in parallel", func() {
+			from, to := assets("examples", "from.yml"), assets("examples", "to.yml")
+
+			expected, err := dyff("between", "--omit-header", from, to)
+			Expect(err).ToNot(HaveOccurred())
+
+			out, err := dyff("between", "--omit-header", "--concurrency", "4", from, to)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(BeEquivalentTo(expected))
+		})
+
+		It("should fail when a list order rule is invalid", func() {
+			_, err := dyff("between", "--omit-header", "--list-order", "hosts=sorted", assets("examples", "from.yml"), assets("examples", "to.yml"))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail when an identifier rule is invalid", func() {
+			_, err := dyff("between", "--omit-header", "--identifier-rule", "ports", assets("examples", "from.yml"), assets("examples", "to.yml"))
+			Expect(err).To(HaveOccurred())
+		})
+
 		It("should ignore the 'whitespace only' changes", func() {
 			out, err := dyff("between",
 				"--omit-header",
@@ -654,4 +961,326 @@ foo: bar
 			Expect(err).To(HaveOccurred())
 		})
 	})
+
+	Context("patch command", func() {
+		var from, to string
+
+		BeforeEach(func() {
+			from = createTestFile(`# deployment defaults
+defaults: &defaults
+  image: nginx:1.0 # the image
+app:
+  <<: *defaults
+  # number of replicas
+  replicas: 1 # count
+  ports:
+    - name: http
+      port: 80
+    - name: https
+      port: 443
+`)
+
+			to = createTestFile(`defaults: &defaults
+  image: nginx:1.0
+app:
+  <<: *defaults
+  replicas: 3
+  ports:
+  - name: https
+    port: 8443
+  - name: http
+    port: 80
+`)
+		})
+
+		AfterEach(func() {
+			os.Remove(from)
+			os.Remove(to)
+		})
+
+		expected := `# deployment defaults
+defaults: &defaults
+  image: nginx:1.0 # the image
+app:
+  <<: *defaults
+  # number of replicas
+  replicas: 3 # count
+  ports:
+    - name: https
+      port: 8443
+    - name: http
+      port: 80
+`
+
+		for _, style := range []string{"json-patch", "merge-patch", "go-patch"} {
+			style := style
+			It(fmt.Sprintf("should apply a %s created by the between command and preserve comments and anchors", style), func() {
+				patch, err := dyff("between", "--output="+style, from, to)
+				Expect(err).ToNot(HaveOccurred())
+
+				patchFile := createTestFile(patch)
+				defer os.Remove(patchFile)
+
+				out, err := dyff("patch", from, patchFile)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(out).To(BeEquivalentTo(expected))
+			})
+		}
+
+		It("should apply the patch in place", func() {
+			patchFile := createTestFile(`[{"op": "replace", "path": "/app/replicas", "value": 2}]`)
+			defer os.Remove(patchFile)
+
+			out, err := dyff("patch", "--in-place", from, patchFile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(BeEmpty())
+
+			data, err := os.ReadFile(from)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring("  replicas: 2 # count\n"))
+		})
+
+		It("should keep strings with a leading newline when applying a JSON patch created by the between command", func() {
+			input := createTestFile(`text: foo`)
+			defer os.Remove(input)
+
+			target := createTestFile(`text: "\nline1\nline2\n"`)
+			defer os.Remove(target)
+
+			patch, err := dyff("between", "--output=json-patch", input, target)
+			Expect(err).ToNot(HaveOccurred())
+
+			patchFile := createTestFile(patch)
+			defer os.Remove(patchFile)
+
+			out, err := dyff("patch", input, patchFile)
+			Expect(err).ToNot(HaveOccurred())
+
+			patched := createTestFile(out)
+			defer os.Remove(patched)
+
+			_, err = dyff("between", "--set-exit-code", patched, target)
+			Expect(err).To(HaveOccurred())
+
+			exitCode, ok := err.(ExitCode)
+			Expect(ok).To(BeTrue())
+			Expect(exitCode.Value()).To(Equal(0))
+		})
+
+		It("should write the result with the indentation of the input", func() {
+			input := createTestFile("app:\n    name: foo\n    ports:\n    -   port: 80\n")
+			defer os.Remove(input)
+
+			patchFile := createTestFile(`[{"op": "replace", "path": "/app/name", "value": "bar"}]`)
+			defer os.Remove(patchFile)
+
+			out, err := dyff("patch", input, patchFile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(BeEquivalentTo("app:\n    name: bar\n    ports:\n        - port: 80\n"))
+		})
+
+		It("should apply one patch per document", func() {
+			input := createTestFile("---\nname: one\n---\nname: two\n")
+			defer os.Remove(input)
+
+			patchFile := createTestFile(`{"name":"uno"}
+{"version":2}
+`)
+			defer os.Remove(patchFile)
+
+			out, err := dyff("patch", input, patchFile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(BeEquivalentTo("---\nname: uno\n---\nname: two\nversion: 2\n"))
+		})
+
+		It("should fail when the number of patches does not match the documents", func() {
+			patchFile := createTestFile("--- {}\n--- {}\n")
+			defer os.Remove(patchFile)
+
+			_, err := dyff("patch", from, patchFile)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("failed to apply patch, it contains 2 patches for 1 documents"))
+		})
+
+		It("should fail when the patch cannot be applied", func() {
+			patchFile := createTestFile(`[{"op": "remove", "path": "/app/missing"}]`)
+			defer os.Remove(patchFile)
+
+			_, err := dyff("patch", "--type", "json-patch", from, patchFile)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("failed to apply patch to document #1"))
+		})
+	})
+
+	Context("merge3 command", func() {
+		var base, ours, theirs string
+
+		BeforeEach(func() {
+			base = createTestFile(`defaults: &defaults
+  image: nginx:1.0
+app:
+  <<: *defaults
+  replicas: 1
+  ports:
+  - name: http
+    port: 80
+`)
+
+			ours = createTestFile(`defaults: &defaults
+  image: nginx:1.0
+app:
+  <<: *defaults
+  # scaled up
+  replicas: 3
+  ports:
+  - name: http
+    port: 80
+`)
+
+			theirs = createTestFile(`defaults: &defaults
+  image: nginx:1.1
+app:
+  <<: *defaults
+  replicas: 1
+  ports:
+  - name: http
+    port: 80
+  - name: https
+    port: 443
+`)
+		})
+
+		AfterEach(func() {
+			os.Remove(base)
+			os.Remove(ours)
+			os.Remove(theirs)
+		})
+
+		It("should write the merged document", func() {
+			out, err := dyff("merge3", base, ours, theirs)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(BeEquivalentTo(`defaults: &defaults
+  image: nginx:1.1
+app:
+  <<: *defaults
+  # scaled up
+  replicas: 3
+  ports:
+    - name: http
+      port: 80
+    - name: https
+      port: 443
+`))
+		})
+
+		It("should overwrite ours with the merged document", func() {
+			out, err := dyff("merge3", "--in-place", base, ours, theirs)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(BeEmpty())
+
+			data, err := os.ReadFile(ours)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(ContainSubstring("image: nginx:1.1"))
+			Expect(string(data)).To(ContainSubstring("replicas: 3"))
+		})
+
+		It("should report conflicts with both sides and exit with code 1", func() {
+			conflicting := createTestFile(`defaults: &defaults
+  image: nginx:1.0
+app:
+  <<: *defaults
+  replicas: 5
+  ports:
+  - name: http
+    port: 80
+`)
+			defer os.Remove(conflicting)
+
+			out, err := dyff("merge3", "--omit-header", base, ours, conflicting)
+			Expect(err).To(HaveOccurred())
+
+			exitCode, ok := err.(ExitCode)
+			Expect(ok).To(BeTrue())
+			Expect(exitCode.Value()).To(Equal(1))
+
+			Expect(out).To(BeEquivalentTo(`
+app.replicas
+  ± conflicting changes
+    ours   theirs
+    3      5
+
+`))
+		})
+	})
+
+	Context("git-merge-driver command", func() {
+		var base, ours, theirs string
+
+		BeforeEach(func() {
+			base = createTestFile(`app:
+  replicas: 1
+  image: nginx:1.0
+`)
+
+			ours = createTestFile(`app:
+  # scaled up
+  replicas: 3
+  image: nginx:1.0
+`)
+
+			theirs = createTestFile(`app:
+  replicas: 1
+  image: nginx:1.1
+`)
+		})
+
+		AfterEach(func() {
+			os.Remove(base)
+			os.Remove(ours)
+			os.Remove(theirs)
+		})
+
+		It("should write the merged document to ours", func() {
+			out, err := dyff("git-merge-driver", base, ours, theirs, "config.yml")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(out).To(BeEmpty())
+
+			data, err := os.ReadFile(ours)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(Equal(`app:
+  # scaled up
+  replicas: 3
+  image: nginx:1.1
+`))
+		})
+
+		It("should leave conflict markers in YAML comments and exit with code 1", func() {
+			conflicting := createTestFile(`app:
+  replicas: 5
+  image: nginx:1.1
+`)
+			defer os.Remove(conflicting)
+
+			_, err := dyff("git-merge-driver", base, ours, conflicting, "config.yml")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("failed to merge config.yml"))
+
+			exitCode, ok := err.(ExitCode)
+			Expect(ok).To(BeTrue())
+			Expect(exitCode.Value()).To(Equal(1))
+
+			data, err := os.ReadFile(ours)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).To(Equal(`app:
+  # <<<<<<< ours
+  # scaled up
+  replicas: 3
+  # =======
+  # replicas: 5
+  # >>>>>>> theirs
+
+  image: nginx:1.1
+`))
+		})
+	})
 })
@@ -51,11 +51,29 @@ type reportConfig struct {
 	IgnoreValueChanges        bool     `mapstructure:"ignore-value-changes"`
 	IgnoreNewDocuments        bool     `mapstructure:"ignore-new-documents"`
 	DetectRenames             bool     `mapstructure:"detect-renames"`
+	DetectEntryRenames        bool     `mapstructure:"detect-entry-renames"`
+	DetectKeyOrderChanges     bool     `mapstructure:"detect-key-order-changes"`
+	DetectMapEntryMoves       bool     `mapstructure:"detect-map-entry-moves"`
+	NullAsAbsent              bool     `mapstructure:"null-as-absent"`
+	EmptyAsAbsent             bool     `mapstructure:"empty-as-absent"`
+	CompareComments           bool     `mapstructure:"compare-comments"`
+	ResolveMergeKeys          bool     `mapstructure:"resolve-merge-keys"`
+	ReportAnchorChanges       bool     `mapstructure:"report-anchor-changes"`
+	NormalizeTags             bool     `mapstructure:"normalize-tags"`
 	MarshalJsonStrings        bool     `mapstructure:"marshal-json-strings"`
 	ChompBlockScalars         bool     `mapstructure:"chomp-block-scalars"`
+	DetectEmbeddedDocuments   bool     `mapstructure:"detect-embedded-documents"`
+	SemanticScalars           bool     `mapstructure:"semantic-scalars"`
+	PositionalLists           bool     `mapstructure:"positional-lists"`
+	FuzzyListPairing          float64  `mapstructure:"fuzzy-list-pairing"`
+	FloatTolerance            float64  `mapstructure:"float-tolerance"`
 	MinorChangeThreshold      float64  `mapstructure:"minor-change-threshold"`
 	MultilineContextLines     int      `mapstructure:"multiline-context-lines"`
+	Concurrency               int      `mapstructure:"concurrency"`
 	AdditionalIdentifiers     []string `mapstructure:"additional-identifier"`
+	IdentifierRules           []string `mapstructure:"identifier-rule"`
+	Schemas                   []string `mapstructure:"schema"`
+	ListOrderRules            []string `mapstructure:"list-order"`
 	Filters                   []string `mapstructure:"filter"`
 	Excludes                  []string `mapstructure:"exclude"`
 	FilterRegexps             []string `mapstructure:"filter-regexp"`
@@ -79,11 +97,29 @@ var defaults = reportConfig{
 	IgnoreValueChanges:        false,
 	IgnoreNewDocuments:        false,
 	DetectRenames:             true,
+	DetectEntryRenames:        false,
+	DetectKeyOrderChanges:     false,
+	DetectMapEntryMoves:       false,
+	NullAsAbsent:              false,
+	EmptyAsAbsent:             false,
+	CompareComments:           false,
+	ResolveMergeKeys:          false,
+	ReportAnchorChanges:       false,
+	NormalizeTags:             false,
 	MarshalJsonStrings:        false,
 	ChompBlockScalars:         false,
+	DetectEmbeddedDocuments:   false,
+	SemanticScalars:           false,
+	PositionalLists:           false,
+	FuzzyListPairing:          0,
+	FloatTolerance:            0,
 	MinorChangeThreshold:      0.1,
 	MultilineContextLines:     4,
+	Concurrency:               1,
 	AdditionalIdentifiers:     nil,
+	IdentifierRules:           nil,
+	Schemas:                   nil,
+	ListOrderRules:            nil,
 	Filters:                   nil,
 	Excludes:                  nil,
 	FilterRegexps:             nil,
@@ -102,10 +138,16 @@ func applyReportOptionsFlags(cmd *cobra.Command) {
 	viper.BindPFlag("ignore-order-changes", cmd.Flags().Lookup("ignore-order-changes"))
 	cmd.Flags().BoolVar(&reportOptions.IgnoreWhitespaceChanges, "ignore-whitespace-changes", defaults.IgnoreWhitespaceChanges, "ignore leading or trailing whitespace changes")
 	viper.BindPFlag("ignore-whitespace-changes", cmd.Flags().Lookup("ignore-whitespace-changes"))
-	cmd.Flags().BoolVarP(&reportOptions.KubernetesEntityDetection, "detect-kubernetes", "", defaults.KubernetesEntityDetection, "detect kubernetes entities")
+	cmd.Flags().BoolVarP(&reportOptions.KubernetesEntityDetection, "detect-kubernetes", "", defaults.KubernetesEntityDetection, "detect kubernetes entities and compare resource quantities by their value")
 	viper.BindPFlag("detect-kubernetes", cmd.Flags().Lookup("detect-kubernetes"))
 	cmd.Flags().StringArrayVar(&reportOptions.AdditionalIdentifiers, "additional-identifier", defaults.AdditionalIdentifiers, "use additional identifier candidates in named entry lists")
 	viper.BindPFlag("additional-identifier", cmd.Flags().Lookup("additional-identifier"))
+	cmd.Flags().StringArrayVar(&reportOptions.IdentifierRules, "identifier-rule", defaults.IdentifierRules, "use the given fields to identify entries of lists matching the path pattern, e.g. 'spec.template.spec.containers.*.ports=containerPort,protocol'")
+	viper.BindPFlag("identifier-rule", cmd.Flags().Lookup("identifier-rule"))
+	cmd.Flags().StringArrayVar(&reportOptions.Schemas, "schema", defaults.Schemas, "load OpenAPI schemas or CustomResourceDefinitions (file or directory) to identify list entries based on the declared list types and keys")
+	viper.BindPFlag("schema", cmd.Flags().Lookup("schema"))
+	cmd.Flags().StringArrayVar(&reportOptions.ListOrderRules, "list-order", defaults.ListOrderRules, "treat lists matching the path pattern as sets or as ordered lists, e.g. 'spec.template.spec.containers.*.env=set' or '**.args=ordered'")
+	viper.BindPFlag("list-order", cmd.Flags().Lookup("list-order"))
 	cmd.Flags().StringSliceVar(&reportOptions.Filters, "filter", defaults.Filters, "filter reports to a subset of differences based on supplied arguments")
 	viper.BindPFlag("filter", cmd.Flags().Lookup("filter"))
 	cmd.Flags().StringSliceVar(&reportOptions.Excludes, "exclude", defaults.Excludes, "exclude reports from a set of differences based on supplied arguments")
@@ -128,13 +170,43 @@ func applyReportOptionsFlags(cmd *cobra.Command) {
 	viper.BindPFlag("ignore-new-documents", betweenCmd.Flags().Lookup("ignore-new-documents"))
 	cmd.Flags().BoolVar(&reportOptions.DetectRenames, "detect-renames", defaults.DetectRenames, "enable detection for renames (document level for Kubernetes resources)")
 	viper.BindPFlag("detect-renames", cmd.Flags().Lookup("detect-renames"))
+	cmd.Flags().BoolVar(&reportOptions.DetectEntryRenames, "detect-entry-renames", defaults.DetectEntryRenames, "enable detection for renames of list entries and map keys based on the similarity of their content")
+	viper.BindPFlag("detect-entry-renames", cmd.Flags().Lookup("detect-entry-renames"))
+	cmd.Flags().BoolVar(&reportOptions.DetectKeyOrderChanges, "detect-key-order-changes", defaults.DetectKeyOrderChanges, "report changes of the order of keys in maps")
+	viper.BindPFlag("detect-key-order-changes", cmd.Flags().Lookup("detect-key-order-changes"))
+	cmd.Flags().BoolVar(&reportOptions.DetectMapEntryMoves, "detect-map-entry-moves", defaults.DetectMapEntryMoves, "report map entries with a map or list value that were moved to a different path instead of a removal and an addition")
+	viper.BindPFlag("detect-map-entry-moves", cmd.Flags().Lookup("detect-map-entry-moves"))
+	cmd.Flags().BoolVar(&reportOptions.NullAsAbsent, "null-as-absent", defaults.NullAsAbsent, "treat null values like absent values")
+	viper.BindPFlag("null-as-absent", cmd.Flags().Lookup("null-as-absent"))
+	cmd.Flags().BoolVar(&reportOptions.EmptyAsAbsent, "empty-as-absent", defaults.EmptyAsAbsent, "treat empty maps, lists, and strings like absent values")
+	viper.BindPFlag("empty-as-absent", cmd.Flags().Lookup("empty-as-absent"))
+	cmd.Flags().BoolVar(&reportOptions.CompareComments, "compare-comments", defaults.CompareComments, "report changes of comments")
+	viper.BindPFlag("compare-comments", cmd.Flags().Lookup("compare-comments"))
+	cmd.Flags().BoolVar(&reportOptions.ResolveMergeKeys, "resolve-merge-keys", defaults.ResolveMergeKeys, "expand merge keys (<<: *anchor) before comparing maps")
+	viper.BindPFlag("resolve-merge-keys", cmd.Flags().Lookup("resolve-merge-keys"))
+	cmd.Flags().BoolVar(&reportOptions.ReportAnchorChanges, "report-anchor-changes", defaults.ReportAnchorChanges, "report changes of anchors, aliases, and merge keys separately from data changes")
+	viper.BindPFlag("report-anchor-changes", cmd.Flags().Lookup("report-anchor-changes"))
+	cmd.Flags().BoolVar(&reportOptions.NormalizeTags, "normalize-tags", defaults.NormalizeTags, "compare values with custom tags by their normalized form, e.g. CloudFormation functions in short form (!Ref X) and long form (Ref: X)")
+	viper.BindPFlag("normalize-tags", cmd.Flags().Lookup("normalize-tags"))
 	cmd.Flags().BoolVar(&reportOptions.MarshalJsonStrings, "marshal-json-strings", defaults.MarshalJsonStrings, "marshal Json strings for comparison, otherwise compare unformatted strings")
 	viper.BindPFlag("marshal-json-strings", cmd.Flags().Lookup("marshal-json-strings"))
 	cmd.Flags().BoolVar(&reportOptions.ChompBlockScalars, "chomp-block-scalars", defaults.ChompBlockScalars, "chomp block scalars for comparison, otherwise compare unformatted strings")
 	viper.BindPFlag("chomp-block-scalars", cmd.Flags().Lookup("chomp-block-scalars"))
+	cmd.Flags().BoolVar(&reportOptions.DetectEmbeddedDocuments, "detect-embedded-documents", defaults.DetectEmbeddedDocuments, "compare JSON, YAML, or TOML documents embedded in strings structurally, otherwise compare them as text")
+	viper.BindPFlag("detect-embedded-documents", cmd.Flags().Lookup("detect-embedded-documents"))
+	cmd.Flags().BoolVar(&reportOptions.SemanticScalars, "semantic-scalars", defaults.SemanticScalars, "compare numbers, timestamps, and booleans by their value, otherwise compare their notation")
+	viper.BindPFlag("semantic-scalars", cmd.Flags().Lookup("semantic-scalars"))
+	cmd.Flags().Float64Var(&reportOptions.FloatTolerance, "float-tolerance", defaults.FloatTolerance, "maximum difference of two numbers to be considered equal when comparing by value")
+	viper.BindPFlag("float-tolerance", cmd.Flags().Lookup("float-tolerance"))
+	cmd.Flags().BoolVar(&reportOptions.PositionalLists, "positional-lists", defaults.PositionalLists, "compare lists without identifiers entry by entry to show where entries were inserted, removed, or modified")
+	viper.BindPFlag("positional-lists", cmd.Flags().Lookup("positional-lists"))
+	cmd.Flags().Float64Var(&reportOptions.FuzzyListPairing, "fuzzy-list-pairing", defaults.FuzzyListPairing, "compare removed and added list entries in detail if their content similarity is above the threshold (between 0 and 1), 0 disables it")
+	viper.BindPFlag("fuzzy-list-pairing", cmd.Flags().Lookup("fuzzy-list-pairing"))
+	cmd.Flags().IntVar(&reportOptions.Concurrency, "concurrency", defaults.Concurrency, "number of documents that are compared in parallel, 0 uses the number of CPUs")
+	viper.BindPFlag("concurrency", cmd.Flags().Lookup("concurrency"))
 
 	// Main output preferences
-	cmd.Flags().StringVarP(&reportOptions.Style, "output", "o", defaults.Style, "specify the output style, supported styles: human, brief, github, gitlab, gitea, yaml")
+	cmd.Flags().StringVarP(&reportOptions.Style, "output", "o", defaults.Style, "specify the output style, supported styles: human, brief, github, gitlab, gitea, yaml, json-patch, merge-patch, go-patch")
 	viper.BindPFlag("output", cmd.Flags().Lookup("output"))
 	cmd.Flags().BoolVarP(&reportOptions.OmitHeader, "omit-header", "b", defaults.OmitHeader, "omit the dyff summary header")
 	viper.BindPFlag("omit-header", cmd.Flags().Lookup("omit-header"))
@@ -337,6 +409,21 @@ func writeReport(cmd *cobra.Command, report dyff.Report) error {
 			Report: report,
 		}
 
+	case "json-patch", "jsonpatch":
+		reportWriter = &dyff.JSONPatchReport{
+			Report: report,
+		}
+
+	case "merge-patch", "mergepatch":
+		reportWriter = &dyff.MergePatchReport{
+			Report: report,
+		}
+
+	case "go-patch", "gopatch", "ops-file":
+		reportWriter = &dyff.GoPatchReport{
+			Report: report,
+		}
+
 	default:
 		return fmt.Errorf("unknown output style %s: %w", reportOptions.Style, fmt.Errorf("%s", cmd.UsageString()))
 	}
@@ -347,13 +434,12 @@ func writeReport(cmd *cobra.Command, report dyff.Report) error {
 
 	// If configured, make sure `dyff` exists with an exit status
 	if reportOptions.ExitWithCode {
-		switch len(report.Diffs) {
-		case 0:
+		// values that are only written differently are no changes
+		if !report.HasChanges() {
 			return errorWithExitCode{value: 0}
-
-		default:
-			return errorWithExitCode{value: 1}
 		}
+
+		return errorWithExitCode{value: 1}
 	}
 
 	return nil
@@ -0,0 +1,104 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/gonvenience/bunt"
+	"github.com/spf13/cobra"
+
+	"github.com/homeport/dyff/pkg/dyff"
+)
+
+var gitMergeDriverCmdSettings mergeOptions
+
+// gitMergeDriverCmd represents the git-merge-driver command
+var gitMergeDriverCmd = &cobra.Command{
+	Use:   "git-merge-driver [flags] <base> <ours> <theirs> [<path>]",
+	Args:  cobra.RangeArgs(3, 4),
+	Short: "Merges YAML files as a Git merge driver",
+	Long: `
+Merges the changes of two versions of a file (ours and theirs) made to their
+common base like the merge3 command, but follows the contract of a Git merge
+driver: The merged result is written to ours, and in case of conflicts, the
+command exits with code 1. Conflicting paths keep the value of ours, which is
+surrounded by conflict markers in YAML comments that also show the value of
+theirs. That way, the merged file remains valid YAML.
+
+To merge YAML files in a Git repository using dyff, configure the merge driver
+and assign it to the files in the .gitattributes file:
+
+  git config merge.dyff.driver 'dyff git-merge-driver %O %A %B %P'
+  echo '*.yml merge=dyff' >> .gitattributes
+`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		base, ours, theirs, err := loadMergeInputs(args[0], args[1], args[2])
+		if err != nil {
+			return err
+		}
+
+		compareOptions, err := gitMergeDriverCmdSettings.compareOptions()
+		if err != nil {
+			return err
+		}
+
+		result, err := dyff.Merge3WithConflictMarkers(base, ours, theirs, compareOptions...)
+		if err != nil {
+			return fmt.Errorf("failed to merge input files: %w", err)
+		}
+
+		var buf bytes.Buffer
+		if err := writeDocuments(&buf, result.Documents, indentation(ours.Documents)); err != nil {
+			return err
+		}
+
+		if err := os.WriteFile(args[1], buf.Bytes(), 0644); err != nil {
+			return fmt.Errorf("failed to write merged result to %s: %w", humanReadableFilename(args[1]), err)
+		}
+
+		if conflicts := result.Conflicts(); len(conflicts) > 0 {
+			// Git passes temporary files, prefer the path name if available
+			name := humanReadableFilename(args[1])
+			if len(args) > 3 {
+				name = args[3]
+			}
+
+			return errorWithExitCode{
+				value: 1,
+				cause: fmt.Errorf("failed to merge %s, %s", name, bunt.Sprintf("found *%d* conflicting changes", len(conflicts))),
+			}
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(gitMergeDriverCmd)
+
+	gitMergeDriverCmd.Flags().SortFlags = false
+
+	applyMergeOptionsFlags(gitMergeDriverCmd, &gitMergeDriverCmdSettings)
+}
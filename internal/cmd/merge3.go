@@ -0,0 +1,188 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/gonvenience/bunt"
+	"github.com/gonvenience/ytbx"
+	"github.com/spf13/cobra"
+
+	"github.com/homeport/dyff/pkg/dyff"
+)
+
+// mergeOptions are the compare settings of the commands that merge input files
+type mergeOptions struct {
+	ignoreOrderChanges        bool
+	kubernetesEntityDetection bool
+	additionalIdentifiers     []string
+	identifierRules           []string
+	listOrderRules            []string
+}
+
+type merge3CmdOptions struct {
+	mergeOptions
+	inplace         bool
+	omitHeader      bool
+	useGoPatchPaths bool
+}
+
+var merge3CmdSettings merge3CmdOptions
+
+// merge3Cmd represents the merge3 command
+var merge3Cmd = &cobra.Command{
+	Use:   "merge3 [flags] <base> <ours> <theirs>",
+	Args:  cobra.ExactArgs(3),
+	Short: "Merges the changes of two input files made to their common base",
+	Long: `
+Merges the changes of two input files (ours and theirs) made to their common
+base structurally: Map entries, list entries, and Kubernetes resources are
+matched like in the between command, changes made by only one side are taken
+over. The merged result keeps the order of keys, comments, and anchors of ours.
+
+In case both sides changed the same path differently, the conflicts are
+reported with both sides shown and the command exits with code 1.
+`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if ytbx.IsStdin(args[1]) && merge3CmdSettings.inplace {
+			return fmt.Errorf("incompatible flags: %w", bunt.Errorf("cannot use in-place flag in combination with input from _*stdin*_"))
+		}
+
+		base, ours, theirs, err := loadMergeInputs(args[0], args[1], args[2])
+		if err != nil {
+			return err
+		}
+
+		compareOptions, err := merge3CmdSettings.compareOptions()
+		if err != nil {
+			return err
+		}
+
+		result, err := dyff.Merge3(base, ours, theirs, compareOptions...)
+		if err != nil {
+			return fmt.Errorf("failed to merge input files: %w", err)
+		}
+
+		if conflicts := result.Conflicts(); len(conflicts) > 0 {
+			report := &dyff.ConflictReport{
+				MergeResult:     result,
+				Indent:          2,
+				OmitHeader:      merge3CmdSettings.omitHeader,
+				UseGoPatchPaths: merge3CmdSettings.useGoPatchPaths,
+			}
+
+			if err := report.WriteReport(os.Stdout); err != nil {
+				return fmt.Errorf("failed to print report: %w", err)
+			}
+
+			return errorWithExitCode{
+				value: 1,
+				cause: fmt.Errorf("failed to merge, %s", bunt.Sprintf("found *%d* conflicting changes", len(conflicts))),
+			}
+		}
+
+		var buf bytes.Buffer
+		if err := writeDocuments(&buf, result.Documents, indentation(ours.Documents)); err != nil {
+			return err
+		}
+
+		if merge3CmdSettings.inplace {
+			if err := os.WriteFile(args[1], buf.Bytes(), 0644); err != nil {
+				return fmt.Errorf("failed to overwrite %s in place: %w", humanReadableFilename(args[1]), err)
+			}
+
+			return nil
+		}
+
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(merge3Cmd)
+
+	merge3Cmd.Flags().SortFlags = false
+
+	merge3Cmd.Flags().BoolVarP(&merge3CmdSettings.inplace, "in-place", "i", false, "overwrite ours with the merged result")
+	applyMergeOptionsFlags(merge3Cmd, &merge3CmdSettings.mergeOptions)
+	merge3Cmd.Flags().BoolVarP(&merge3CmdSettings.omitHeader, "omit-header", "b", false, "omit the summary header of the conflict report")
+	merge3Cmd.Flags().BoolVarP(&merge3CmdSettings.useGoPatchPaths, "use-go-patch-style", "g", false, "use Go-Patch style paths in the conflict report")
+}
+
+func applyMergeOptionsFlags(cmd *cobra.Command, options *mergeOptions) {
+	cmd.Flags().BoolVar(&options.ignoreOrderChanges, "ignore-order-changes", false, "ignore order changes in lists")
+	cmd.Flags().BoolVar(&options.kubernetesEntityDetection, "detect-kubernetes", true, "detect kubernetes entities")
+	cmd.Flags().StringArrayVar(&options.additionalIdentifiers, "additional-identifier", nil, "use additional identifier candidates in named entry lists")
+	cmd.Flags().StringArrayVar(&options.identifierRules, "identifier-rule", nil, "use the given fields to identify entries of lists matching the path pattern, e.g. 'spec.template.spec.containers.*.ports=containerPort,protocol'")
+	cmd.Flags().StringArrayVar(&options.listOrderRules, "list-order", nil, "treat lists matching the path pattern as sets or as ordered lists, e.g. 'spec.template.spec.containers.*.env=set' or '**.args=ordered'")
+}
+
+// compareOptions returns the compare options that match the merge settings
+func (options mergeOptions) compareOptions() ([]dyff.CompareOption, error) {
+	var identifierRules []dyff.IdentifierRule
+	for _, rule := range options.identifierRules {
+		identifierRule, err := dyff.ParseIdentifierRule(rule)
+		if err != nil {
+			return nil, err
+		}
+
+		identifierRules = append(identifierRules, identifierRule)
+	}
+
+	var listOrderRules []dyff.ListOrderRule
+	for _, rule := range options.listOrderRules {
+		listOrderRule, err := dyff.ParseListOrderRule(rule)
+		if err != nil {
+			return nil, err
+		}
+
+		listOrderRules = append(listOrderRules, listOrderRule)
+	}
+
+	return []dyff.CompareOption{
+		dyff.IgnoreOrderChanges(options.ignoreOrderChanges),
+		dyff.KubernetesEntityDetection(options.kubernetesEntityDetection),
+		dyff.KubernetesQuantities(options.kubernetesEntityDetection),
+		dyff.AdditionalIdentifiers(options.additionalIdentifiers...),
+		dyff.IdentifierRules(identifierRules...),
+		dyff.ListOrderRules(listOrderRules...),
+	}, nil
+}
+
+// loadMergeInputs loads the base, ours, and theirs input files of a merge
+func loadMergeInputs(baseLocation, oursLocation, theirsLocation string) (ytbx.InputFile, ytbx.InputFile, ytbx.InputFile, error) {
+	var inputFiles [3]ytbx.InputFile
+	for i, location := range []string{baseLocation, oursLocation, theirsLocation} {
+		inputFile, err := ytbx.LoadFile(location)
+		if err != nil {
+			return ytbx.InputFile{}, ytbx.InputFile{}, ytbx.InputFile{}, fmt.Errorf("failed to load input from %s: %w", humanReadableFilename(location), err)
+		}
+
+		inputFiles[i] = inputFile
+	}
+
+	return inputFiles[0], inputFiles[1], inputFiles[2], nil
+}
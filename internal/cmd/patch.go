@@ -0,0 +1,300 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/gonvenience/bunt"
+	"github.com/gonvenience/ytbx"
+	"github.com/spf13/cobra"
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/homeport/dyff/pkg/dyff"
+)
+
+type patchCmdOptions struct {
+	patchType string
+	inplace   bool
+}
+
+var patchCmdSettings patchCmdOptions
+
+// patchCmd represents the patch command
+var patchCmd = &cobra.Command{
+	Use:   "patch [flags] <file-location> <patch-location>",
+	Args:  cobra.ExactArgs(2),
+	Short: "Applies a JSON Patch, JSON Merge Patch, or go-patch operations file",
+	Long: `
+Applies a JSON Patch (RFC 6902), JSON Merge Patch (RFC 7386), or go-patch
+operations file (BOSH ops file) to the input documents, for example a patch
+created with 'dyff between --output json-patch'. The type of the patch is
+detected automatically unless it is specified.
+
+The patch is applied to the YAML structure, so that the order of keys, comments,
+and anchors are preserved. The result is written with the indentation of the
+input, except for lists, which are always indented below their key. In case of
+input files with multiple documents, the patch needs to contain one patch per
+document.
+`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filename, patchLocation := args[0], args[1]
+
+		if ytbx.IsStdin(filename) && patchCmdSettings.inplace {
+			return fmt.Errorf("incompatible flags: %w", bunt.Errorf("cannot use in-place flag in combination with input from _*stdin*_"))
+		}
+
+		inputFile, err := ytbx.LoadFile(filename)
+		if err != nil {
+			return fmt.Errorf("failed to load input from %s: %w", humanReadableFilename(filename), err)
+		}
+
+		patches, err := loadPatches(patchLocation)
+		if err != nil {
+			return fmt.Errorf("failed to load patch from %s: %w", humanReadableFilename(patchLocation), err)
+		}
+
+		if len(patches) != len(inputFile.Documents) {
+			return fmt.Errorf("failed to apply patch, it contains %d patches for %d documents", len(patches), len(inputFile.Documents))
+		}
+
+		indent := indentation(inputFile.Documents)
+		documents := make([]*yamlv3.Node, len(inputFile.Documents))
+		for i, document := range inputFile.Documents {
+			if documents[i], err = applyPatch(document, patches[i]); err != nil {
+				return fmt.Errorf("failed to apply patch to document #%d: %w", i+1, err)
+			}
+		}
+
+		var buf bytes.Buffer
+		if err := writeDocuments(&buf, documents, indent); err != nil {
+			return err
+		}
+
+		if patchCmdSettings.inplace {
+			if err := os.WriteFile(filename, buf.Bytes(), 0644); err != nil {
+				return fmt.Errorf("failed to overwrite %s in place: %w", humanReadableFilename(filename), err)
+			}
+
+			return nil
+		}
+
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(patchCmd)
+
+	patchCmd.Flags().SortFlags = false
+
+	patchCmd.Flags().StringVar(&patchCmdSettings.patchType, "type", "auto", "type of the patch, supported types: auto, json-patch, merge-patch, go-patch")
+	patchCmd.Flags().BoolVarP(&patchCmdSettings.inplace, "in-place", "i", false, "overwrite input file with output of this command")
+}
+
+// loadPatches reads the patch for each document, which are either separate
+// YAML documents, or JSON values on separate lines
+func loadPatches(location string) ([][]byte, error) {
+	var data []byte
+	var err error
+	if ytbx.IsStdin(location) {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(location)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var result [][]byte
+	var decodeErr error
+	decoder := yamlv3.NewDecoder(bytes.NewReader(data))
+	for {
+		var node yamlv3.Node
+		if decodeErr = decoder.Decode(&node); decodeErr != nil {
+			break
+		}
+
+		patch, err := yamlv3.Marshal(&node)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, patch)
+	}
+
+	if errors.Is(decodeErr, io.EOF) {
+		return result, nil
+	}
+
+	// multiple JSON values on separate lines are not a valid YAML document
+	result = nil
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) != "" {
+			result = append(result, []byte(line))
+		}
+	}
+
+	return result, nil
+}
+
+func applyPatch(document *yamlv3.Node, patch []byte) (*yamlv3.Node, error) {
+	patchType := strings.ToLower(patchCmdSettings.patchType)
+	if patchType == "auto" {
+		var err error
+		if patchType, err = detectPatchType(patch); err != nil {
+			return nil, err
+		}
+	}
+
+	switch patchType {
+	case "json-patch", "jsonpatch":
+		return dyff.ApplyJSONPatch(document, patch)
+
+	case "merge-patch", "mergepatch":
+		return dyff.ApplyMergePatch(document, patch)
+
+	case "go-patch", "gopatch", "ops-file":
+		return dyff.ApplyGoPatch(document, patch)
+	}
+
+	return nil, fmt.Errorf("unknown patch type %s", patchCmdSettings.patchType)
+}
+
+// detectPatchType returns the type of the patch based on its structure, a map
+// is a merge patch, a list of operations with `op` is a JSON Patch, and a list
+// of operations with `type` is a go-patch operations file
+func detectPatchType(patch []byte) (string, error) {
+	var node yamlv3.Node
+	if err := yamlv3.Unmarshal(patch, &node); err != nil {
+		return "", err
+	}
+
+	if len(node.Content) == 0 {
+		return "merge-patch", nil
+	}
+
+	switch content := node.Content[0]; content.Kind {
+	case yamlv3.MappingNode:
+		return "merge-patch", nil
+
+	case yamlv3.SequenceNode:
+		if len(content.Content) == 0 {
+			return "json-patch", nil
+		}
+
+		if first := content.Content[0]; first.Kind == yamlv3.MappingNode {
+			for i := 0; i+1 < len(first.Content); i += 2 {
+				switch first.Content[i].Value {
+				case "op":
+					return "json-patch", nil
+
+				case "type":
+					return "go-patch", nil
+				}
+			}
+		}
+	}
+
+	return "", fmt.Errorf("unable to detect the type of the patch, use the type flag to specify it")
+}
+
+// implicitMergeKeys removes the explicit tag of merge keys, which would
+// otherwise be written as `!!merge <<`
+func implicitMergeKeys(node *yamlv3.Node) {
+	if node.Kind == yamlv3.ScalarNode && node.Tag == "!!merge" && node.Value == "<<" {
+		node.Tag = ""
+	}
+
+	for _, child := range node.Content {
+		implicitMergeKeys(child)
+	}
+}
+
+// indentation returns the number of spaces that nested maps of the documents
+// are indented with, or two in case the documents have no nested block maps
+func indentation(documents []*yamlv3.Node) int {
+	var find func(node *yamlv3.Node) int
+	find = func(node *yamlv3.Node) int {
+		if node.Kind == yamlv3.MappingNode && node.Style&yamlv3.FlowStyle == 0 {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				key, value := node.Content[i], node.Content[i+1]
+				if value.Kind == yamlv3.MappingNode && value.Style&yamlv3.FlowStyle == 0 && len(value.Content) > 0 && value.Content[0].Line > key.Line {
+					if indent := value.Content[0].Column - key.Column; indent > 0 {
+						return indent
+					}
+				}
+			}
+		}
+
+		for _, child := range node.Content {
+			if indent := find(child); indent > 0 {
+				return indent
+			}
+		}
+
+		return 0
+	}
+
+	for _, document := range documents {
+		if indent := find(document); indent > 0 {
+			return indent
+		}
+	}
+
+	return 2
+}
+
+// writeDocuments writes the documents as YAML using the provided indentation,
+// separated by the document start marker in case there is more than one
+// document. Lists are always indented below their key, since the encoder does
+// not support lists that start in the same column as their key.
+func writeDocuments(out io.Writer, documents []*yamlv3.Node, indent int) error {
+	writer := bufio.NewWriter(out)
+	for _, document := range documents {
+		if len(documents) > 1 {
+			fmt.Fprintln(writer, "---")
+		}
+
+		implicitMergeKeys(document)
+
+		encoder := yamlv3.NewEncoder(writer)
+		encoder.SetIndent(indent)
+		if err := encoder.Encode(document); err != nil {
+			return err
+		}
+
+		if err := encoder.Close(); err != nil {
+			return err
+		}
+	}
+
+	return writer.Flush()
+}
@@ -64,6 +64,9 @@ func ResetSettings() {
 	betweenCmdSettings = betweenCmdOptions{}
 	yamlCmdSettings = yamlCmdOptions{}
 	jsonCmdSettings = jsonCmdOptions{}
+	patchCmdSettings = patchCmdOptions{patchType: "auto"}
+	merge3CmdSettings = merge3CmdOptions{mergeOptions: mergeOptions{kubernetesEntityDetection: true}}
+	gitMergeDriverCmdSettings = mergeOptions{kubernetesEntityDetection: true}
 }
 
 // rearrange will rearrange the OS args to match `dyff between --flags from to`
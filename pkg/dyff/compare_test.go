@@ -21,6 +21,13 @@
 package dyff_test
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
@@ -704,12 +711,13 @@ listY: [ Yo, Yo, Yo ]
 				}
 			})
 
-			It("should fail to compare files with different number of documents", func() {
+			It("should compare files with different number of documents", func() {
 				from := ytbx.InputFile{Location: "/ginkgo/compare/test/from", Documents: multiDoc("foo: bar", "dead: beef")}
 				to := ytbx.InputFile{Location: "/ginkgo/compare/test/to", Documents: multiDoc("bar: foo")}
 
-				_, err := dyff.CompareInputFiles(from, to)
-				Expect(err).To(HaveOccurred())
+				results, err := dyff.CompareInputFiles(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(results.Diffs).To(HaveLen(3))
 			})
 
 			It("should return differences in named lists even if no standard identifier is used", func() {
@@ -1100,17 +1108,1568 @@ b: bar
 			})
 		})
 
-		Context("input files containing complex objects with custom keys", func() {
-			It("cannot determine the keys through heuristics", func() {
-				from, to, err := ytbx.LoadFiles(assets("issues", "issue-243", "to.yml"), assets("issues", "issue-243", "from.yml"))
-				Expect(err).To(BeNil())
-				Expect(from).ToNot(BeNil())
-				Expect(to).ToNot(BeNil())
+		Context("lists compared by position", func() {
+			It("should report insertions and removals at their index", func() {
+				from := yml(`{"args": ["--verbose", "--port", "8080", "--log-format", "json", "--debug"]}`)
+				to := yml(`{"args": ["--verbose", "--host", "0.0.0.0", "--port", "8080", "--log-format", "json"]}`)
 
-				results, err := dyff.CompareInputFiles(from, to, dyff.IgnoreOrderChanges(true))
+				result, err := compare(from, to, dyff.PositionalLists(true))
 				Expect(err).ToNot(HaveOccurred())
-				Expect(results).ToNot(BeNil())
-				Expect(results.Diffs).ToNot(HaveLen(0))
+				Expect(result).To(HaveLen(2))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/args/1", dyff.ADDITION, nil, list(`[--host, 0.0.0.0]`))))
+				Expect(result[1]).To(BeSameDiffAs(singleDiff("/args/5", dyff.REMOVAL, list(`[--debug]`), nil)))
+			})
+
+			It("should compare entries that were replaced at the same position", func() {
+				from := yml(`---
+rules:
+- {action: allow, port: 22, source: 10.0.0.0/8}
+- {action: allow, port: 443, source: 0.0.0.0/0}
+- {action: deny, port: 0, source: 0.0.0.0/0}
+`)
+
+				to := yml(`---
+rules:
+- {action: allow, port: 22, source: 10.0.0.0/8}
+- {action: allow, port: 8443, source: 0.0.0.0/0}
+- {action: deny, port: 0, source: 0.0.0.0/0}
+`)
+
+				result, err := compare(from, to, dyff.PositionalLists(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/rules/1/port", dyff.MODIFICATION, 443, 8443)))
+			})
+
+			It("should not be used for lists where the order is irrelevant", func() {
+				from := yml(`{"list": [1, 2, 3]}`)
+				to := yml(`{"list": [3, 2, 1, 4]}`)
+
+				result, err := compare(from, to, dyff.PositionalLists(true), dyff.IgnoreOrderChanges(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/list", dyff.ADDITION, nil, list(`[4]`))))
+			})
+
+			It("should consider entries identical regardless of the order of their keys", func() {
+				from := yml(`{"rules": [{action: allow, port: 22}, {action: deny, port: 0}]}`)
+				to := yml(`{"rules": [{port: 22, action: allow}, {action: deny, port: 0}, {port: 80, action: allow}]}`)
+
+				result, err := compare(from, to, dyff.PositionalLists(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/rules/2", dyff.ADDITION, nil, list(`[{port: 80, action: allow}]`))))
+			})
+		})
+
+		Context("fuzzy pairing of list entries", func() {
+			var from, to *yamlv3.Node
+
+			BeforeEach(func() {
+				from = yml(`---
+list:
+- {type: ingress, port: 22, cidr: 10.0.0.0/8, description: ssh}
+- {type: ingress, port: 443, cidr: 0.0.0.0/0, description: https}
+- {type: egress, port: 0, cidr: 0.0.0.0/0, description: all}
+`)
+
+				to = yml(`---
+list:
+- {type: ingress, port: 22, cidr: 10.0.0.0/8, description: ssh}
+- {type: ingress, port: 443, cidr: 10.0.0.0/16, description: https}
+- {type: egress, port: 53, cidr: 8.8.8.8/32, description: dns}
+`)
+			})
+
+			It("should compare similar entries in detail if enabled", func() {
+				result, err := compare(from, to, dyff.FuzzyListPairing(0.6))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(2))
+				Expect(result[0]).To(BeSameDiffAs(doubleDiff("/list",
+					dyff.REMOVAL, list(`[{type: egress, port: 0, cidr: 0.0.0.0/0, description: all}]`), nil,
+					dyff.ADDITION, nil, list(`[{type: egress, port: 53, cidr: 8.8.8.8/32, description: dns}]`),
+				)))
+				Expect(result[1]).To(BeSameDiffAs(singleDiff("/list/1/cidr", dyff.MODIFICATION, "0.0.0.0/0", "10.0.0.0/16")))
+			})
+
+			It("should report order changes of similar entries that swapped positions", func() {
+				from := yml(`---
+list:
+- {type: ingress, port: 22, cidr: 10.0.0.0/8, description: ssh}
+- {type: ingress, port: 443, cidr: 0.0.0.0/0, description: https}
+`)
+
+				to := yml(`---
+list:
+- {type: ingress, port: 443, cidr: 10.0.0.0/16, description: https}
+- {type: ingress, port: 22, cidr: 10.0.0.0/24, description: ssh}
+`)
+
+				result, err := compare(from, to, dyff.FuzzyListPairing(0.6))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(3))
+				Expect(result[0].Path.String()).To(Equal("/list"))
+				Expect(result[0].Details).To(HaveLen(1))
+				Expect(result[0].Details[0].Kind).To(Equal(dyff.ORDERCHANGE))
+				Expect(result[1]).To(BeSameDiffAs(singleDiff("/list/0/cidr", dyff.MODIFICATION, "10.0.0.0/8", "10.0.0.0/24")))
+				Expect(result[2]).To(BeSameDiffAs(singleDiff("/list/1/cidr", dyff.MODIFICATION, "0.0.0.0/0", "10.0.0.0/16")))
+			})
+
+			It("should report whole entries if disabled", func() {
+				result, err := compare(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Details).To(HaveLen(2))
+				Expect(result[0].Details[0].From.Content).To(HaveLen(2))
+				Expect(result[0].Details[1].To.Content).To(HaveLen(2))
+			})
+		})
+
+		Context("list order rules", func() {
+			var from, to *yamlv3.Node
+
+			BeforeEach(func() {
+				from = yml(`---
+spec:
+  containers:
+  - name: app
+    args: [--verbose, --debug]
+    env:
+    - {name: LOG_LEVEL, value: info}
+    - {name: PORT, value: "8080"}
+`)
+
+				to = yml(`---
+spec:
+  containers:
+  - name: app
+    args: [--debug, --verbose]
+    env:
+    - {name: PORT, value: "8080"}
+    - {name: LOG_LEVEL, value: info}
+`)
+			})
+
+			It("should ignore order changes in lists that are marked as sets", func() {
+				result, err := compare(from, to, dyff.ListOrderRules(dyff.ListOrderRule{PathPattern: "**.env"}))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Path.ToGoPatchStyle()).To(Equal("/spec/containers/name=app/args"))
+				Expect(result[0].Details[0].Kind).To(Equal(dyff.ORDERCHANGE))
+			})
+
+			It("should report order changes in lists that are marked as ordered", func() {
+				result, err := compare(from, to,
+					dyff.IgnoreOrderChanges(true),
+					dyff.ListOrderRules(dyff.ListOrderRule{PathPattern: "spec.containers.*.args", Ordered: true}),
+				)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Path.ToGoPatchStyle()).To(Equal("/spec/containers/name=app/args"))
+				Expect(result[0].Details[0].Kind).To(Equal(dyff.ORDERCHANGE))
+			})
+
+			It("should consider sets inside of list entries when matching entries", func() {
+				from := yml(`{"list": [{"ports": [80, 443]}, {"ports": [22]}]}`)
+				to := yml(`{"list": [{"ports": [22]}, {"ports": [443, 80]}]}`)
+
+				result, err := compare(from, to, dyff.ListOrderRules(dyff.ListOrderRule{PathPattern: "list.*.ports"}))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Details).To(HaveLen(1))
+				Expect(result[0].Details[0].Kind).To(Equal(dyff.ORDERCHANGE))
+			})
+
+			It("should not modify the input when order changes are ignored", func() {
+				from := yml(`{"list": [{"ports": [80, 443]}, {"ports": [22]}]}`)
+				to := yml(`{"list": [{"ports": [22]}, {"ports": [443, 80]}, {"ports": [8080]}]}`)
+
+				_, err := compare(from, to, dyff.IgnoreOrderChanges(true))
+				Expect(err).ToNot(HaveOccurred())
+
+				ports, err := ytbx.Grab(from, "/list/0/ports")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(ports.Content[0].Value).To(Equal("80"))
+				Expect(ports.Content[1].Value).To(Equal("443"))
+			})
+
+			It("should parse rules from their text representation", func() {
+				Expect(dyff.ParseListOrderRule("**.env=set")).To(Equal(dyff.ListOrderRule{PathPattern: "**.env", Ordered: false}))
+				Expect(dyff.ParseListOrderRule("/spec/args=ordered")).To(Equal(dyff.ListOrderRule{PathPattern: "/spec/args", Ordered: true}))
+
+				_, err := dyff.ParseListOrderRule("**.env=sorted")
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("key order changes", func() {
+			It("should report changes of the order of common keys if enabled", func() {
+				from := yml(`{"name": "foo", "image": "foo:1.0", "ports": [80]}`)
+				to := yml(`{"image": "foo:1.0", "command": "run", "name": "foo"}`)
+
+				result, err := compare(from, to, dyff.DetectKeyOrderChanges(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Details).To(HaveLen(3))
+				Expect(result[0].Details[0]).To(Equal(dyff.Detail{
+					Kind: dyff.ORDERCHANGE,
+					From: dyff.AsSequenceNode("name", "image"),
+					To:   dyff.AsSequenceNode("image", "name"),
+				}))
+				Expect(result[0].Details[1].Kind).To(Equal(dyff.REMOVAL))
+				Expect(result[0].Details[2].Kind).To(Equal(dyff.ADDITION))
+			})
+
+			It("should not report changes of the order of keys by default", func() {
+				from := yml(`{"name": "foo", "image": "foo:1.0"}`)
+				to := yml(`{"image": "foo:1.0", "name": "foo"}`)
+
+				result, err := compare(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(0))
+			})
+		})
+
+		Context("null and empty values treated like absent values", func() {
+			It("should treat null values like absent map entries", func() {
+				from := yml(`{"name": "foo", "labels": null}`)
+				to := yml(`{"name": "foo", "selector": null}`)
+
+				result, err := compare(from, to, dyff.NullAsAbsent(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(0))
+
+				result, err = compare(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Details).To(HaveLen(2))
+			})
+
+			It("should treat empty maps, lists, and strings like absent map entries", func() {
+				from := yml(`{"name": "foo", "annotations": {}, "args": []}`)
+				to := yml(`{"name": "foo", "description": ""}`)
+
+				result, err := compare(from, to, dyff.EmptyAsAbsent(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(0))
+			})
+
+			It("should consider null and empty values to be equal", func() {
+				from := yml(`{"annotations": null}`)
+				to := yml(`{"annotations": {}}`)
+
+				result, err := compare(from, to, dyff.NullAsAbsent(true), dyff.EmptyAsAbsent(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(0))
+
+				result, err = compare(from, to, dyff.NullAsAbsent(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+			})
+
+			It("should still report empty values that replace a value", func() {
+				from := yml(`{"annotations": {"foo": "bar"}}`)
+				to := yml(`{"annotations": {}}`)
+
+				result, err := compare(from, to, dyff.EmptyAsAbsent(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/annotations", dyff.REMOVAL, yml(`{"foo": "bar"}`), nil)))
+			})
+
+			It("should ignore empty documents", func() {
+				from := ytbx.InputFile{Documents: multiDoc("foo: bar", "{}")}
+				to := ytbx.InputFile{Documents: multiDoc("foo: bar")}
+
+				report, err := dyff.CompareInputFiles(from, to, dyff.EmptyAsAbsent(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(0))
+
+				report, err = dyff.CompareInputFiles(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(1))
+			})
+		})
+
+		Context("comments", func() {
+			var from, to *yamlv3.Node
+
+			BeforeEach(func() {
+				from = yml(`---
+image:
+  repository: nginx
+  # deprecated: use image.repository
+  name: nginx
+replicas: 1 # number of pods
+containers:
+- name: app
+  image: app:1.0
+`)
+
+				to = yml(`---
+image:
+  repository: nginx
+  name: nginx
+replicas: 1 # number of replicas
+containers:
+# the main container
+- name: app
+  image: app:1.0
+`)
+			})
+
+			It("should report changes of comments at the path of the node they belong to", func() {
+				result, err := compare(from, to, dyff.CompareComments(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(3))
+
+				Expect(result[0].Path.ToGoPatchStyle()).To(Equal("/image/name"))
+				Expect(result[0].Details).To(Equal([]dyff.Detail{{
+					Kind: dyff.COMMENT,
+					From: &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: "# deprecated: use image.repository"},
+					To:   nil,
+					Note: "head comment",
+				}}))
+
+				Expect(result[1].Path.ToGoPatchStyle()).To(Equal("/replicas"))
+				Expect(result[1].Details).To(HaveLen(1))
+				Expect(result[1].Details[0].Note).To(Equal("line comment"))
+				Expect(result[1].Details[0].From.Value).To(Equal("# number of pods"))
+				Expect(result[1].Details[0].To.Value).To(Equal("# number of replicas"))
+
+				Expect(result[2].Path.ToGoPatchStyle()).To(Equal("/containers/name=app"))
+				Expect(result[2].Details).To(HaveLen(1))
+				Expect(result[2].Details[0].From).To(BeNil())
+				Expect(result[2].Details[0].To.Value).To(Equal("# the main container"))
+			})
+
+			It("should not report changes of comments by default", func() {
+				result, err := compare(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(0))
+			})
+
+			It("should report changes of comments of unchanged entries in simple lists", func() {
+				from := yml(`---
+args:
+- --verbose # more output
+- --port
+- {level: debug}
+`)
+
+				to := yml(`---
+args:
+- --port
+- --verbose # most output
+- {level: debug} # log level
+`)
+
+				result, err := compare(from, to, dyff.CompareComments(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(3))
+
+				Expect(result[0].Path.ToGoPatchStyle()).To(Equal("/args"))
+				Expect(result[0].Details).To(HaveLen(1))
+				Expect(result[0].Details[0].Kind).To(Equal(dyff.ORDERCHANGE))
+
+				Expect(result[1].Path.ToGoPatchStyle()).To(Equal("/args/0"))
+				Expect(result[1].Details).To(Equal([]dyff.Detail{{
+					Kind: dyff.COMMENT,
+					From: &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: "# more output"},
+					To:   &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: "# most output"},
+					Note: "line comment",
+				}}))
+
+				Expect(result[2].Path.ToGoPatchStyle()).To(Equal("/args/2"))
+				Expect(result[2].Details).To(HaveLen(1))
+				Expect(result[2].Details[0].From).To(BeNil())
+				Expect(result[2].Details[0].To.Value).To(Equal("# log level"))
+			})
+		})
+
+		Context("merge keys and anchors", func() {
+			var from, to *yamlv3.Node
+
+			BeforeEach(func() {
+				from = yml(`---
+defaults: &defaults
+  adapter: postgres
+  host: localhost
+development:
+  adapter: postgres
+  host: localhost
+  database: dev
+test:
+  <<: *defaults
+  database: test
+`)
+
+				to = yml(`---
+defaults: &defaults
+  adapter: postgres
+  host: localhost
+development:
+  <<: *defaults
+  database: dev
+test:
+  <<: *defaults
+  host: db
+  database: test
+`)
+			})
+
+			It("should compare maps by their effective content", func() {
+				result, err := compare(from, to, dyff.ResolveMergeKeys(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/test/host", dyff.MODIFICATION, "localhost", "db")))
+			})
+
+			It("should report changes of merge keys separately if enabled", func() {
+				result, err := compare(from, to, dyff.ResolveMergeKeys(true), dyff.ReportAnchorChanges(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(2))
+				Expect(result[0].Path.ToGoPatchStyle()).To(Equal("/development"))
+				Expect(result[0].Details).To(HaveLen(1))
+				Expect(result[0].Details[0].Kind).To(Equal(dyff.ANCHOR))
+				Expect(result[0].Details[0].From).To(BeNil())
+				Expect(result[0].Details[0].To.Value).To(Equal("merge *defaults"))
+				Expect(result[1]).To(BeSameDiffAs(singleDiff("/test/host", dyff.MODIFICATION, "localhost", "db")))
+			})
+
+			It("should compare merge keys literally by default", func() {
+				result, err := compare(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(2))
+				Expect(result[0].Path.ToGoPatchStyle()).To(Equal("/development"))
+				Expect(result[1].Path.ToGoPatchStyle()).To(Equal("/test"))
+			})
+
+			It("should report values that were replaced with an alias", func() {
+				from := yml(`{"image": &img "nginx", "sidecar": "nginx"}`)
+				to := yml(`{"image": &img "nginx", "sidecar": *img}`)
+
+				result, err := compare(from, to, dyff.ReportAnchorChanges(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Path.ToGoPatchStyle()).To(Equal("/sidecar"))
+				Expect(result[0].Details[0].Kind).To(Equal(dyff.ANCHOR))
+				Expect(result[0].Details[0].From).To(BeNil())
+				Expect(result[0].Details[0].To.Value).To(Equal("alias *img"))
+			})
+
+			It("should report anchor changes separately from changes of the value", func() {
+				from := yml(`{"image": &img "nginx", "sidecar": "busybox"}`)
+				to := yml(`{"image": &img "nginx", "sidecar": *img}`)
+
+				result, err := compare(from, to, dyff.ReportAnchorChanges(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(2))
+				Expect(result[0].Path.ToGoPatchStyle()).To(Equal("/sidecar"))
+				Expect(result[0].Details).To(HaveLen(1))
+				Expect(result[0].Details[0].Kind).To(Equal(dyff.ANCHOR))
+				Expect(result[0].Details[0].To.Value).To(Equal("alias *img"))
+				Expect(result[1]).To(BeSameDiffAs(singleDiff("/sidecar", dyff.MODIFICATION, "busybox", "nginx")))
+			})
+
+			It("should report anchor changes of list entries", func() {
+				from := yml(`---
+base: &base {x: 1}
+simple: [{x: 1}, two]
+named: [{name: app, image: v1}, {name: db, image: v1}]
+`)
+
+				to := yml(`---
+base: &base {x: 1}
+simple: [*base, two]
+named: [&app {name: app, image: v2}, {name: db, image: v1}]
+`)
+
+				result, err := compare(from, to, dyff.ReportAnchorChanges(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(3))
+
+				Expect(result[0].Path.ToGoPatchStyle()).To(Equal("/simple/0"))
+				Expect(result[0].Details).To(HaveLen(1))
+				Expect(result[0].Details[0].Kind).To(Equal(dyff.ANCHOR))
+				Expect(result[0].Details[0].From).To(BeNil())
+				Expect(result[0].Details[0].To.Value).To(Equal("alias *base"))
+
+				Expect(result[1].Path.ToGoPatchStyle()).To(Equal("/named/name=app"))
+				Expect(result[1].Details).To(HaveLen(1))
+				Expect(result[1].Details[0].Kind).To(Equal(dyff.ANCHOR))
+				Expect(result[1].Details[0].To.Value).To(Equal("anchor &app"))
+
+				Expect(result[2]).To(BeSameDiffAs(singleDiff("/named/name=app/image", dyff.MODIFICATION, "v1", "v2")))
+			})
+
+			It("should report anchor changes of document roots", func() {
+				from := ytbx.InputFile{Documents: multiDoc(`&config {"image": "nginx"}`)}
+				to := ytbx.InputFile{Documents: multiDoc(`{"image": "nginx"}`)}
+
+				report, err := dyff.CompareInputFiles(from, to, dyff.ReportAnchorChanges(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(1))
+				Expect(report.Diffs[0].Path.ToGoPatchStyle()).To(Equal("/"))
+				Expect(report.Diffs[0].Details).To(HaveLen(1))
+				Expect(report.Diffs[0].Details[0].Kind).To(Equal(dyff.ANCHOR))
+				Expect(report.Diffs[0].Details[0].From.Value).To(Equal("anchor &config"))
+				Expect(report.Diffs[0].Details[0].To).To(BeNil())
+			})
+		})
+
+		Context("custom tags", func() {
+			It("should consider short and long form of CloudFormation functions to be equal", func() {
+				from := yml(`---
+Resources:
+  Bucket:
+    Properties:
+      BucketName: !Sub "${AWS::StackName}-logs"
+      Target: !GetAtt Queue.Arn
+      Policies: [!Ref Policy]
+`)
+
+				to := yml(`---
+Resources:
+  Bucket:
+    Properties:
+      BucketName: {"Fn::Sub": "${AWS::StackName}-logs"}
+      Target: {"Fn::GetAtt": [Queue, Arn]}
+      Policies: [{Ref: Policy}]
+`)
+
+				result, err := compare(from, to, dyff.NormalizeTags(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(0))
+
+				result, err = compare(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(3))
+			})
+
+			It("should compare the normalized values in detail", func() {
+				result, err := compare(yml(`{"bucket": !Ref Bucket}`), yml(`{"bucket": {"Ref": "Logs"}}`), dyff.NormalizeTags(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/bucket/Ref", dyff.MODIFICATION, "Bucket", "Logs")))
+			})
+
+			It("should use registered tag normalizers", func() {
+				dyff.RegisterTagNormalizer("!Vault", func(node *yamlv3.Node) *yamlv3.Node {
+					return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: "vault:" + node.Value}
+				})
+
+				result, err := compare(yml(`{"password": !Vault secret/db}`), yml(`{"password": "vault:secret/db"}`), dyff.NormalizeTags(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(0))
+			})
+		})
+
+		Context("moves within documents", func() {
+			It("should report subtrees that were moved to a different path", func() {
+				from := yml(`---
+spec:
+  securityContext: {runAsNonRoot: true}
+  template:
+    spec:
+      containers: [{name: app, image: "app:1.0"}]
+`)
+
+				to := yml(`---
+spec:
+  template:
+    spec:
+      securityContext: {runAsNonRoot: true}
+      containers: [{name: app, image: "app:1.0"}]
+`)
+
+				result, err := compare(from, to, dyff.DetectMapEntryMoves(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Path.ToGoPatchStyle()).To(Equal("/spec/template/spec/securityContext"))
+				Expect(result[0].Details).To(HaveLen(1))
+				Expect(result[0].Details[0].Kind).To(Equal(dyff.MOVE))
+				Expect(result[0].Details[0].Source.ToGoPatchStyle()).To(Equal("/spec/securityContext"))
+			})
+
+			It("should report the changes of subtrees that were moved and modified", func() {
+				from := yml(`---
+spec:
+  securityContext: {runAsNonRoot: true, runAsUser: 1000, fsGroup: 2000}
+  replicas: 1
+  template:
+    spec: {}
+`)
+
+				to := yml(`---
+spec:
+  template:
+    spec:
+      securityContext: {runAsNonRoot: true, runAsUser: 1001, fsGroup: 2000}
+  replicas: 1
+`)
+
+				result, err := compare(from, to, dyff.DetectMapEntryMoves(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(2))
+				Expect(result[0].Details[0].Kind).To(Equal(dyff.MOVE))
+				Expect(result[0].Details[0].Source.ToGoPatchStyle()).To(Equal("/spec/securityContext"))
+				Expect(result[1]).To(BeSameDiffAs(singleDiff("/spec/template/spec/securityContext/runAsUser", dyff.MODIFICATION, 1000, 1001)))
+			})
+
+			It("should keep the other entries that were removed or added", func() {
+				from := yml(`{"a": {"config": {"x": 1, "y": 2}, "other": {"z": 3}}, "b": {}}`)
+				to := yml(`{"a": {}, "b": {"config": {"x": 1, "y": 2}, "new": {"z": 4}}}`)
+
+				result, err := compare(from, to, dyff.DetectMapEntryMoves(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(3))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/a", dyff.REMOVAL, yml(`{"other": {"z": 3}}`), nil)))
+				Expect(result[1]).To(BeSameDiffAs(singleDiff("/b", dyff.ADDITION, nil, yml(`{"new": {"z": 4}}`))))
+				Expect(result[2].Details[0].Kind).To(Equal(dyff.MOVE))
+				Expect(result[2].Path.ToGoPatchStyle()).To(Equal("/b/config"))
+			})
+
+			It("should not report list entries that were moved to a different list", func() {
+				from := yml(`{"a": [{"x": 1, "y": 2}, "keep"], "b": ["keep"]}`)
+				to := yml(`{"a": ["keep"], "b": [{"x": 1, "y": 2}, "keep"]}`)
+
+				result, err := compare(from, to, dyff.DetectMapEntryMoves(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(2))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/a", dyff.REMOVAL, list(`[{"x": 1, "y": 2}]`), nil)))
+				Expect(result[1]).To(BeSameDiffAs(singleDiff("/b", dyff.ADDITION, nil, list(`[{"x": 1, "y": 2}]`))))
+			})
+
+			It("should report removals and additions if disabled", func() {
+				from := yml(`{"a": {"config": {"x": 1, "y": 2}}, "b": {}}`)
+				to := yml(`{"a": {}, "b": {"config": {"x": 1, "y": 2}}}`)
+
+				result, err := compare(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(2))
+				Expect(result[0].Details[0].Kind).To(Equal(dyff.REMOVAL))
+				Expect(result[1].Details[0].Kind).To(Equal(dyff.ADDITION))
+			})
+		})
+
+		Context("parallel comparison of documents", func() {
+			documents := func(count int, image string, modified func(int) bool) ytbx.InputFile {
+				var docs []string
+				for i := 0; i < count; i++ {
+					version := "v1"
+					if modified(i) {
+						version = image
+					}
+
+					docs = append(docs, fmt.Sprintf(`{"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "app-%d"}, "spec": {"image": "%s"}}`, i, version))
+				}
+
+				return ytbx.InputFile{Location: "/ginkgo/compare/test", Documents: multiDoc(docs...)}
+			}
+
+			everyThird := func(i int) bool { return i%3 == 0 }
+			all := func(int) bool { return true }
+
+			It("should report the same differences in the same order as the sequential comparison", func() {
+				from, to := documents(50, "v1", everyThird), documents(50, "v2", everyThird)
+
+				for _, options := range [][]dyff.CompareOption{
+					{},
+					{dyff.KubernetesEntityDetection(false)},
+				} {
+					sequential, err := dyff.CompareInputFiles(from, to, append(options, dyff.Concurrency(1))...)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(sequential.Diffs).To(HaveLen(17))
+
+					parallel, err := dyff.CompareInputFiles(from, to, append(options, dyff.Concurrency(8))...)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(parallel.Diffs).To(Equal(sequential.Diffs))
+				}
+			})
+
+			It("should compare documents in parallel in case the number of documents differs", func() {
+				from, to := documents(50, "v1", all), documents(50, "v2", all)
+				from.Documents = append(from.Documents, multiDoc(`{"name": "anchor"}`, `{"name": "removed"}`)...)
+				to.Documents = append(to.Documents, multiDoc(`{"name": "anchor"}`)...)
+
+				sequential, err := dyff.CompareInputFiles(from, to, dyff.KubernetesEntityDetection(false), dyff.Concurrency(1))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(sequential.Diffs).To(HaveLen(51))
+
+				parallel, err := dyff.CompareInputFiles(from, to, dyff.KubernetesEntityDetection(false), dyff.Concurrency(0))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(parallel.Diffs).To(Equal(sequential.Diffs))
+			})
+		})
+
+		Context("comparing nodes and values", func() {
+			It("should compare nodes that are not documents", func() {
+				report, err := dyff.CompareNodes(yml(`{"name": "foo", "replicas": 1}`), yml(`{"name": "foo", "replicas": 2}`))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.From.Location).To(Equal("from"))
+				Expect(report.To.Location).To(Equal("to"))
+				Expect(report.Diffs).To(HaveLen(1))
+				Expect(report.Diffs[0]).To(BeSameDiffAs(singleDiff("/replicas", dyff.MODIFICATION, 1, 2)))
+			})
+
+			It("should report a node as added in case there is none to compare with", func() {
+				report, err := dyff.CompareNodes(nil, yml(`{"name": "foo"}`))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(1))
+				Expect(report.Diffs[0].Details).To(HaveLen(1))
+				Expect(report.Diffs[0].Details[0].Kind).To(Equal(dyff.ADDITION))
+			})
+
+			It("should compare Go values", func() {
+				type container struct {
+					Name  string   `yaml:"name"`
+					Image string   `yaml:"image"`
+					Args  []string `yaml:"args,omitempty"`
+				}
+
+				from := map[string]any{"containers": []container{{Name: "app", Image: "app:1.0"}}}
+				to := map[string]any{"containers": []container{{Name: "app", Image: "app:1.1", Args: []string{"--debug"}}}}
+
+				report, err := dyff.CompareValues(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(HaveLen(2))
+				Expect(report.Diffs[0].Path.String()).To(Equal("/containers/name=app"))
+				Expect(report.Diffs[0].Details[0].Kind).To(Equal(dyff.ADDITION))
+				Expect(report.Diffs[1]).To(BeSameDiffAs(singleDiff("/containers/name=app/image", dyff.MODIFICATION, "app:1.0", "app:1.1")))
+			})
+
+			It("should fail for values that cannot be represented in YAML", func() {
+				_, err := dyff.CompareValues(map[string]any{"a": 1}, map[string]any{"a": make(chan int)})
+				Expect(err).To(HaveOccurred())
+			})
+
+			It("should create reports that work with all report writers and filters", func() {
+				report, err := dyff.CompareValues(
+					map[string]any{"a": 1, "b": map[string]any{"c": "foo"}},
+					map[string]any{"a": 2, "b": map[string]any{"c": "bar"}},
+				)
+				Expect(err).ToNot(HaveOccurred())
+
+				filtered := report.Filter("/b/c")
+				Expect(filtered.Diffs).To(HaveLen(1))
+				Expect(report.FilterRegexp("^/a$").Diffs).To(HaveLen(1))
+				Expect(report.FilterDocument("document #1").Diffs).To(HaveLen(2))
+
+				for _, writer := range []dyff.ReportWriter{
+					&dyff.HumanReport{Report: filtered, OmitHeader: true},
+					&dyff.BriefReport{Report: filtered},
+					&dyff.DiffSyntaxReport{HumanReport: dyff.HumanReport{Report: filtered}},
+					&dyff.YAMLReport{Report: filtered},
+				} {
+					var buf bytes.Buffer
+					Expect(writer.WriteReport(&buf)).To(Succeed())
+					Expect(buf.String()).ToNot(BeEmpty(), "%T", writer)
+				}
+			})
+		})
+
+		Context("untrusted input", func() {
+			billionLaughs := `---
+a: &a ["lol", "lol", "lol", "lol", "lol", "lol", "lol", "lol", "lol"]
+b: &b [*a, *a, *a, *a, *a, *a, *a, *a, *a]
+c: &c [*b, *b, *b, *b, *b, *b, *b, *b, *b]
+d: &d [*c, *c, *c, *c, *c, *c, *c, *c, *c]
+e: &e [*d, *d, *d, *d, *d, *d, *d, *d, *d]
+f: &f [*e, *e, *e, *e, *e, *e, *e, *e, *e]
+g: &g [*f, *f, *f, *f, *f, *f, *f, *f, *f]
+`
+
+			inputFile := func(input string) ytbx.InputFile {
+				return ytbx.InputFile{Location: "/ginkgo/compare/test", Documents: multiDoc(input)}
+			}
+
+			It("should stop the comparison when the context is cancelled", func() {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+
+				_, err := dyff.CompareInputFilesContext(ctx, inputFile(`{"key": "foo"}`), inputFile(`{"key": "bar"}`))
+				Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+			})
+
+			It("should stop the comparison when the timeout expires while similar list entries are searched", func() {
+				// entries without any common values, so that every removed entry is
+				// compared with every added entry without finding a similar one
+				var from, to strings.Builder
+				for i := 0; i < 3000; i++ {
+					fmt.Fprintf(&from, "- {a: %d, b: %d}\n", i%50, i/50)
+					fmt.Fprintf(&to, "- {a: %d, b: %d}\n", 1000+i%50, 1000+i/50)
+				}
+
+				ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+				defer cancel()
+
+				start := time.Now()
+				_, err := dyff.CompareInputFilesContext(ctx, inputFile(from.String()), inputFile(to.String()), dyff.FuzzyListPairing(0.5))
+				Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+				Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+			})
+
+			It("should reject inputs that exceed the number of alias expansions", func() {
+				_, err := dyff.CompareInputFilesContext(context.Background(), inputFile(billionLaughs), inputFile(`{"a": "lol"}`), dyff.MaxAliasExpansions(1000))
+
+				var limitErr *dyff.LimitError
+				Expect(errors.As(err, &limitErr)).To(BeTrue())
+				Expect(limitErr.Limit).To(Equal(dyff.LimitAliasExpansions))
+				Expect(limitErr.Location).To(Equal("/ginkgo/compare/test"))
+				Expect(err.Error()).To(Equal("/ginkgo/compare/test exceeds the maximum number of alias expansions of 1000"))
+			})
+
+			It("should reject inputs that exceed the number of nodes", func() {
+				_, err := dyff.CompareInputFilesContext(context.Background(), inputFile(`{"a": "lol"}`), inputFile(billionLaughs), dyff.MaxNodes(100000))
+
+				var limitErr *dyff.LimitError
+				Expect(errors.As(err, &limitErr)).To(BeTrue())
+				Expect(limitErr.Limit).To(Equal(dyff.LimitNodes))
+			})
+
+			It("should reject inputs that exceed the depth", func() {
+				_, err := dyff.CompareInputFilesContext(context.Background(), inputFile(`{"a": {"b": {"c": {"d": 1}}}}`), inputFile(`{"a": 1}`), dyff.MaxDepth(4))
+
+				var limitErr *dyff.LimitError
+				Expect(errors.As(err, &limitErr)).To(BeTrue())
+				Expect(limitErr.Limit).To(Equal(dyff.LimitDepth))
+			})
+
+			It("should reject embedded documents that exceed the limits", func() {
+				from := inputFile(`{"config": "{\"a\": {\"b\": {\"c\": 1}}}"}`)
+				to := inputFile(`{"config": "{\"a\": {\"b\": {\"c\": 2}}}"}`)
+
+				_, err := dyff.CompareInputFilesContext(context.Background(), from, to, dyff.DetectEmbeddedDocuments(true), dyff.MaxDepth(4))
+
+				var limitErr *dyff.LimitError
+				Expect(errors.As(err, &limitErr)).To(BeTrue())
+				Expect(limitErr.Location).To(Equal("embedded document at /config"))
+			})
+
+			It("should compare inputs that are within the limits", func() {
+				report, err := dyff.CompareInputFilesContext(context.Background(), inputFile(billionLaughs), inputFile(billionLaughs),
+					dyff.MaxDepth(10),
+					dyff.MaxNodes(10000000),
+					dyff.MaxAliasExpansions(1000000),
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(report.Diffs).To(BeEmpty())
+			})
+
+			It("should return unexpected failures as errors", func() {
+				dyff.RegisterTagNormalizer("!Explode", func(*yamlv3.Node) *yamlv3.Node {
+					panic("boom")
+				})
+
+				for _, concurrency := range []int{1, 2} {
+					_, err := dyff.CompareInputFilesContext(context.Background(),
+						inputFile("---\n- !Explode foo\n---\n- bar\n"),
+						inputFile("---\n- foo\n---\n- baz\n"),
+						dyff.NormalizeTags(true),
+						dyff.KubernetesEntityDetection(false),
+						dyff.Concurrency(concurrency),
+					)
+
+					var panicErr *dyff.PanicError
+					Expect(errors.As(err, &panicErr)).To(BeTrue())
+					Expect(panicErr.Value).To(Equal("boom"))
+					Expect(panicErr.Stack).ToNot(BeEmpty())
+				}
+			})
+		})
+
+		Context("renames within documents", func() {
+			It("should detect renamed list entries and compare their content", func() {
+				from := yml(`---
+spec:
+  containers:
+  - name: app
+    image: registry/app:1.0.0
+    args: [--port, "8080"]
+    env:
+    - name: LOG_LEVEL
+      value: info
+  - name: sidecar
+    image: registry/proxy:2.1.0
+`)
+
+				to := yml(`---
+spec:
+  containers:
+  - name: web
+    image: registry/app:1.1.0
+    args: [--port, "8080"]
+    env:
+    - name: LOG_LEVEL
+      value: info
+  - name: sidecar
+    image: registry/proxy:2.1.0
+`)
+
+				result, err := compare(from, to, dyff.DetectEntryRenames(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(2))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/spec/containers/name=app", dyff.RENAME, "app", "web")))
+				Expect(result[1]).To(BeSameDiffAs(singleDiff("/spec/containers/name=app/image", dyff.MODIFICATION, "registry/app:1.0.0", "registry/app:1.1.0")))
+
+				result, err = compare(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Details).To(HaveLen(2))
+			})
+
+			It("should detect renamed mapping keys with similar values", func() {
+				from := yml(`---
+databases:
+  primary:
+    host: db-1.example.com
+    port: 5432
+    user: admin
+  cache:
+    host: redis.example.com
+`)
+
+				to := yml(`---
+databases:
+  main:
+    host: db-1.example.com
+    port: 5433
+    user: admin
+  cache:
+    host: redis.example.com
+`)
+
+				result, err := compare(from, to, dyff.DetectEntryRenames(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(2))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/databases/primary", dyff.RENAME, "primary", "main")))
+				Expect(result[1]).To(BeSameDiffAs(singleDiff("/databases/primary/port", dyff.MODIFICATION, 5432, 5433)))
+			})
+
+			It("should detect renamed list entries with only one field next to the identifier", func() {
+				from := yml(`{"containers": [{"name": "web", "image": "nginx:1.0"}]}`)
+				to := yml(`{"containers": [{"name": "frontend", "image": "nginx:1.1"}]}`)
+
+				result, err := compare(from, to, dyff.DetectEntryRenames(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(2))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/containers/name=web", dyff.RENAME, "web", "frontend")))
+				Expect(result[1]).To(BeSameDiffAs(singleDiff("/containers/name=web/image", dyff.MODIFICATION, "nginx:1.0", "nginx:1.1")))
+			})
+
+			It("should not consider entries with different content as renamed", func() {
+				from := yml(`{"list": [{"name": "one", "foo": "bar", "x": 1}]}`)
+				to := yml(`{"list": [{"name": "two", "foo": "baz", "x": 2}]}`)
+
+				result, err := compare(from, to, dyff.DetectEntryRenames(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Details).To(HaveLen(2))
+				Expect(result[0].Details[0].Kind).To(BeEquivalentTo(dyff.REMOVAL))
+				Expect(result[0].Details[1].Kind).To(BeEquivalentTo(dyff.ADDITION))
+			})
+		})
+
+		Context("identifier rules", func() {
+			It("should use multiple fields to identify list entries", func() {
+				from := yml(`---
+ports:
+- containerPort: 80
+  protocol: TCP
+  name: http
+- containerPort: 80
+  protocol: UDP
+  name: quic
+`)
+
+				to := yml(`---
+ports:
+- containerPort: 80
+  protocol: UDP
+  name: quic
+- containerPort: 80
+  protocol: TCP
+  name: web
+`)
+
+				result, err := compare(from, to,
+					dyff.IgnoreOrderChanges(true),
+					dyff.IdentifierRules(dyff.IdentifierRule{PathPattern: "ports", Fields: []string{"containerPort", "protocol"}}),
+				)
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Path.ToDotStyle()).To(Equal("ports.containerPort=80,protocol=TCP.name"))
+				Expect(result[0].Details).To(HaveLen(1))
+				Expect(result[0].Details[0].Kind).To(BeEquivalentTo(dyff.MODIFICATION))
+				Expect(result[0].Details[0].From.Value).To(Equal("http"))
+				Expect(result[0].Details[0].To.Value).To(Equal("web"))
+			})
+
+			It("should only apply rules to lists with a matching path", func() {
+				from := yml(`---
+spec:
+  containers:
+  - name: app
+    volumeMounts:
+    - name: data
+      mountPath: /data
+      readOnly: true
+    - name: data
+      mountPath: /backup
+  volumes:
+  - name: data
+    emptyDir: {}
+`)
+
+				to := yml(`---
+spec:
+  containers:
+  - name: app
+    volumeMounts:
+    - name: data
+      mountPath: /data
+      readOnly: false
+    - name: data
+      mountPath: /backup
+  volumes:
+  - name: data
+    emptyDir:
+      medium: Memory
+`)
+
+				result, err := compare(from, to, dyff.IdentifierRules(
+					dyff.IdentifierRule{PathPattern: "**.volumeMounts", Fields: []string{"mountPath", "name"}},
+					dyff.IdentifierRule{PathPattern: "/spec/volumes", Fields: []string{"emptyDir"}},
+				))
+
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(2))
+				Expect(result[0].Path.ToGoPatchStyle()).To(Equal("/spec/containers/name=app/volumeMounts/mountPath=/data,name=data/readOnly"))
+				Expect(result[1].Path.ToGoPatchStyle()).To(Equal("/spec/volumes/name=data/emptyDir"))
+			})
+
+			It("should parse identifier rules from their text representation", func() {
+				rule, err := dyff.ParseIdentifierRule("/spec/containers/name=app/ports=containerPort,protocol")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(rule).To(Equal(dyff.IdentifierRule{
+					PathPattern: "/spec/containers/name=app/ports",
+					Fields:      []string{"containerPort", "protocol"},
+				}))
+
+				_, err = dyff.ParseIdentifierRule("spec.containers")
+				Expect(err).To(HaveOccurred())
+
+				_, err = dyff.ParseIdentifierRule("spec.containers=")
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("Kubernetes schemas", func() {
+			It("should use the list map keys of OpenAPI schemas to identify list entries", func() {
+				schemas, err := dyff.LoadSchemas(assets("schemas", "openapi-v3.json"))
+				Expect(err).ToNot(HaveOccurred())
+
+				from := yml(`---
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        ports:
+        - containerPort: 80
+          protocol: TCP
+          name: http
+        - containerPort: 443
+          protocol: TCP
+          name: https
+`)
+
+				to := yml(`---
+apiVersion: apps/v1
+kind: Deployment
+spec:
+  template:
+    spec:
+      containers:
+      - name: app
+        ports:
+        - containerPort: 80
+          protocol: TCP
+          name: web
+        - containerPort: 443
+          protocol: TCP
+          name: https
+`)
+
+				result, err := compare(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Details).To(HaveLen(2))
+
+				result, err = compare(from, to, dyff.KubernetesSchemas(schemas))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Path.ToGoPatchStyle()).To(Equal("/spec/template/spec/containers/name=app/ports/containerPort=80,protocol=TCP/name"))
+				Expect(result[0].Details).To(HaveLen(1))
+				Expect(result[0].Details[0].Kind).To(BeEquivalentTo(dyff.MODIFICATION))
+			})
+
+			It("should use the list types of CustomResourceDefinitions", func() {
+				schemas, err := dyff.LoadSchemas(assets("schemas", "crd.yml"))
+				Expect(err).ToNot(HaveOccurred())
+
+				from := yml(`---
+apiVersion: example.com/v1
+kind: Gateway
+spec:
+  hostnames:
+  - foo.example.com
+  - bar.example.com
+  listeners:
+  - port: 443
+    protocol: HTTPS
+    hostname: foo.example.com
+  - port: 443
+    protocol: TLS
+    hostname: bar.example.com
+  steps:
+  - name: one
+  - name: two
+`)
+
+				to := yml(`---
+apiVersion: example.com/v1
+kind: Gateway
+spec:
+  hostnames:
+  - bar.example.com
+  - foo.example.com
+  listeners:
+  - port: 443
+    protocol: TLS
+    hostname: bar.example.com
+  - port: 443
+    protocol: HTTPS
+    hostname: baz.example.com
+  steps:
+  - name: one
+  - name: two
+  - name: three
+`)
+
+				result, err := compare(from, to, dyff.KubernetesSchemas(schemas))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(3))
+
+				Expect(result[0].Path.ToGoPatchStyle()).To(Equal("/spec/listeners"))
+				Expect(result[0].Details).To(HaveLen(1))
+				Expect(result[0].Details[0].Kind).To(BeEquivalentTo(dyff.ORDERCHANGE))
+
+				Expect(result[1].Path.ToGoPatchStyle()).To(Equal("/spec/listeners/port=443,protocol=HTTPS/hostname"))
+
+				Expect(result[2]).To(BeSameDiffAs(singleDiff("/spec/steps", dyff.ADDITION, nil, list(`[{name: three}]`))))
+			})
+
+			It("should fail to load files that are not schemas", func() {
+				_, err := dyff.LoadSchemas(assets("examples", "from.yml"))
+				Expect(err).To(HaveOccurred())
+			})
+		})
+
+		Context("Kubernetes resource quantities", func() {
+			It("should not report quantities that only differ in their notation", func() {
+				from := yml(`---
+apiVersion: v1
+kind: Pod
+resources:
+  requests:
+    cpu: 500m
+    memory: 1Gi
+    ephemeral-storage: 2G
+  limits:
+    cpu: 1
+    memory: 1536Mi
+`)
+
+				to := yml(`---
+apiVersion: v1
+kind: Pod
+resources:
+  requests:
+    cpu: 0.5
+    memory: 1024Mi
+    ephemeral-storage: 2e9
+  limits:
+    cpu: 1000m
+    memory: 1.5Gi
+`)
+
+				result, err := compare(from, to, dyff.KubernetesQuantities(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeEmpty())
+
+				result, err = compare(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(5))
+			})
+
+			It("should report quantity changes with the normalized delta", func() {
+				from := yml(`---
+apiVersion: v1
+kind: Pod
+resources:
+  requests:
+    cpu: 500m
+    memory: 1Gi
+  limits:
+    cpu: 2
+`)
+
+				to := yml(`---
+apiVersion: v1
+kind: Pod
+resources:
+  requests:
+    cpu: 0.75
+    memory: 1280Mi
+  limits:
+    cpu: 1500m
+`)
+
+				result, err := compare(from, to, dyff.KubernetesQuantities(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(3))
+
+				notes := map[string]string{}
+				for _, diff := range result {
+					Expect(diff.Details).To(HaveLen(1))
+					Expect(diff.Details[0].Kind).To(BeEquivalentTo(dyff.MODIFICATION))
+					notes[diff.Path.ToGoPatchStyle()] = diff.Details[0].Note
+				}
+
+				Expect(notes).To(Equal(map[string]string{
+					"/resources/requests/cpu":    "+250m",
+					"/resources/requests/memory": "+256Mi",
+					"/resources/limits/cpu":      "-500m",
+				}))
+			})
+
+			It("should not compare values outside of quantity fields as quantities", func() {
+				from := yml(`{"apiVersion": "v1", "kind": "Pod", "spec": {"size": "1Gi", "limits": {"memory": "unlimited"}}}`)
+				to := yml(`{"apiVersion": "v1", "kind": "Pod", "spec": {"size": "1024Mi", "limits": {"memory": "none"}}}`)
+
+				result, err := compare(from, to, dyff.KubernetesQuantities(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(2))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/spec/size", dyff.MODIFICATION, "1Gi", "1024Mi")))
+				Expect(result[1]).To(BeSameDiffAs(singleDiff("/spec/limits/memory", dyff.MODIFICATION, "unlimited", "none")))
+			})
+
+			It("should not compare values of documents that are no Kubernetes resources as quantities", func() {
+				from := yml(`{"limits": {"retries": 1e3, "memory": 1Gi}}`)
+				to := yml(`{"limits": {"retries": 1000, "memory": 1024Mi}}`)
+
+				result, err := compare(from, to, dyff.KubernetesQuantities(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(2))
+				Expect(result[0].Path.ToGoPatchStyle()).To(Equal("/limits/retries"))
+				Expect(result[0].Details[0].Note).To(BeEmpty())
+				Expect(result[1]).To(BeSameDiffAs(singleDiff("/limits/memory", dyff.MODIFICATION, "1Gi", "1024Mi")))
+			})
+
+			It("should consider list entries with equivalent quantities to be the same entries", func() {
+				from := yml(`{"apiVersion": "v1", "kind": "Pod", "items": [{"limits": {"memory": "1Gi"}}, {"limits": {"memory": "2Gi"}}]}`)
+				to := yml(`{"apiVersion": "v1", "kind": "Pod", "items": [{"limits": {"memory": "1024Mi"}}, {"limits": {"memory": "2048Mi"}}]}`)
+
+				result, err := compare(from, to, dyff.KubernetesQuantities(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeEmpty())
+			})
+		})
+
+		Context("scalars compared by their value", func() {
+			It("should report numbers, timestamps, or booleans that are only written differently as equivalent", func() {
+				from := yml(`---
+float: 1.0
+hex: 0x10
+exponent: 1e3
+octal: 0o17
+timestamp: 2001-12-14t21:59:43.10-05:00
+date: 2002-12-14
+bool: true
+`)
+
+				to := yml(`---
+float: 1
+hex: 16
+exponent: 1000
+octal: 15
+timestamp: 2001-12-15T02:59:43.1Z
+date: 2002-12-14T00:00:00Z
+bool: "yes"
+`)
+
+				result, err := compare(from, to, dyff.SemanticScalars(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(7))
+				for _, diff := range result {
+					Expect(diff.Details).To(HaveLen(1))
+					Expect(diff.Details[0].Note).To(Equal("equivalent value"))
+				}
+
+				result, err = compare(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(7))
+				for _, diff := range result {
+					Expect(diff.Details[0].Note).To(BeEmpty())
+				}
+			})
+
+			It("should report actual value changes across number types", func() {
+				from := yml(`{"value": 1}`)
+				to := yml(`{"value": 1.5}`)
+
+				result, err := compare(from, to, dyff.SemanticScalars(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/value", dyff.MODIFICATION, 1, 1.5)))
+			})
+
+			It("should consider the configured tolerance when comparing numbers", func() {
+				from := yml(`{"a": 0.3, "b": 1, "c": 1.0, "d": 1.5}`)
+				to := yml(`{"a": 0.30000001, "b": 1.1, "c": 1, "d": 1.5}`)
+
+				result, err := compare(from, to, dyff.SemanticScalars(true), dyff.FloatTolerance(0.001))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(3))
+				Expect(result[0].Path.String()).To(Equal("/a"))
+				Expect(result[0].Details[0].Note).To(Equal("within tolerance"))
+				Expect(result[1]).To(BeSameDiffAs(singleDiff("/b", dyff.MODIFICATION, 1, 1.1)))
+				Expect(result[2].Path.String()).To(Equal("/c"))
+				Expect(result[2].Details[0].Note).To(Equal("equivalent value"))
+
+				report := dyff.Report{Diffs: result}
+				Expect(report.HasChanges()).To(BeTrue())
+
+				report.Diffs = []dyff.Diff{result[0], result[2]}
+				Expect(report.HasChanges()).To(BeFalse())
+			})
+
+			It("should compare large integers without losing precision", func() {
+				from := yml(`{"a": 9007199254740993, "b": 9007199254740993, "c": 123456789012345678901234567890}`)
+				to := yml(`{"a": 9007199254740992, "b": 9007199254740993, "c": 123456789012345678901234567891}`)
+
+				result, err := compare(from, to, dyff.SemanticScalars(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(2))
+				Expect(result[0].Path.String()).To(Equal("/a"))
+				Expect(result[0].Details[0].Note).To(BeEmpty())
+				Expect(result[1].Path.String()).To(Equal("/c"))
+				Expect(result[1].Details[0].Note).To(BeEmpty())
+
+				result, err = compare(from, to, dyff.SemanticScalars(true), dyff.FloatTolerance(1))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(2))
+				Expect(result[0].Details[0].Note).To(Equal("within tolerance"))
+				Expect(result[1].Details[0].Note).To(Equal("within tolerance"))
+			})
+
+			It("should consider entries of simple lists with equivalent values to be the same entries", func() {
+				from := yml(`{"list": [1.0, 0x10, 2001-12-14t21:59:43.10-05:00]}`)
+				to := yml(`{"list": [1, 16, 2001-12-15T02:59:43.1Z]}`)
+
+				result, err := compare(from, to, dyff.SemanticScalars(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(BeEmpty())
+
+				result, err = compare(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Details).To(HaveLen(2))
+			})
+		})
+
+		Context("strings that contain embedded documents", func() {
+
+			It("should compare embedded JSON documents structurally if configured", func() {
+				from := yml(`---
+data:
+  config.json: |
+    {"server": {"port": 8080, "host": "localhost"}, "debug": false}
+`)
+
+				to := yml(`---
+data:
+  config.json: |
+    {
+      "server": {"port": 9090, "host": "localhost"},
+      "debug": false
+    }
+`)
+
+				result, err := compare(from, to, dyff.DetectEmbeddedDocuments(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/data/config.json/server/port", dyff.MODIFICATION, 8080, 9090)))
+				Expect(result[0].Details[0].Embedding.ToGoPatchStyle()).To(Equal("/data/config.json"))
+
+				result, err = compare(from, to)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0].Path.ToGoPatchStyle()).To(Equal("/data/config.json"))
+			})
+
+			It("should compare embedded YAML documents structurally if configured", func() {
+				from := yml(`---
+data:
+  app.yaml: |
+    logging:
+      level: info
+    features:
+    - name: foo
+      enabled: true
+`)
+
+				to := yml(`---
+data:
+  app.yaml: |
+    logging:
+      level: debug
+    features:
+    - name: foo
+      enabled: true
+`)
+
+				result, err := compare(from, to, dyff.DetectEmbeddedDocuments(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/data/app.yaml/logging/level", dyff.MODIFICATION, "info", "debug")))
+			})
+
+			It("should fall back to a text comparison if only one side contains a document", func() {
+				from := yml(`{"key": "{\"foo\": \"bar\"}"}`)
+				to := yml(`{"key": "foo: bar"}`)
+
+				result, err := compare(from, to, dyff.DetectEmbeddedDocuments(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result).To(HaveLen(1))
+				Expect(result[0]).To(BeSameDiffAs(singleDiff("/key", dyff.MODIFICATION, `{"foo": "bar"}`, "foo: bar")))
+			})
+		})
+
+		Context("input files with a different number of documents", func() {
+			It("should report a document that was added in between other documents", func() {
+				from := ytbx.InputFile{
+					Location:  "/ginkgo/compare/test/from",
+					Documents: multiDoc(`{"name": "one", "value": 1}`, `{"name": "three", "value": 3}`),
+				}
+
+				to := ytbx.InputFile{
+					Location:  "/ginkgo/compare/test/to",
+					Documents: multiDoc(`{"name": "one", "value": 1}`, `{"name": "two", "value": 2}`, `{"name": "three", "value": 3}`),
+				}
+
+				result, err := dyff.CompareInputFiles(from, to)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Diffs).To(HaveLen(1))
+				Expect(result.Diffs[0].Path.DocumentIdx).To(Equal(1))
+				Expect(result.Diffs[0].Details).To(HaveLen(1))
+				Expect(result.Diffs[0].Details[0].Kind).To(Equal(dyff.ADDITION))
+				Expect(result.Diffs[0].Details[0].To.Kind).To(Equal(yamlv3.DocumentNode))
+			})
+
+			It("should report a removed document and compare the documents that line up", func() {
+				from := ytbx.InputFile{
+					Location: "/ginkgo/compare/test/from",
+					Documents: multiDoc(
+						`{"jobs": [{"name": "build", "plan": [{"get": "source"}, {"task": "compile"}, {"task": "unit"}]}]}`,
+						`{"resources": [{"name": "source", "type": "git", "uri": "https://example.org/repo.git"}]}`,
+						`{"groups": [{"name": "all", "jobs": ["build"]}]}`,
+					),
+				}
+
+				to := ytbx.InputFile{
+					Location: "/ginkgo/compare/test/to",
+					Documents: multiDoc(
+						`{"jobs": [{"name": "build", "plan": [{"get": "source"}, {"task": "compile"}, {"task": "unit"}]}]}`,
+						`{"resources": [{"name": "source", "type": "git", "uri": "https://example.org/other.git"}]}`,
+					),
+				}
+
+				result, err := dyff.CompareInputFiles(from, to)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Diffs).To(HaveLen(2))
+				Expect(result.Diffs[0]).To(BeSameDiffAs(singleDiff(
+					"#1/resources/name=source/uri",
+					dyff.MODIFICATION,
+					"https://example.org/repo.git",
+					"https://example.org/other.git",
+				)))
+				Expect(result.Diffs[1].Path.DocumentIdx).To(Equal(2))
+				Expect(result.Diffs[1].Details).To(HaveLen(1))
+				Expect(result.Diffs[1].Details[0].Kind).To(Equal(dyff.REMOVAL))
+			})
+
+			It("should report additions and removals of documents that do not line up at all", func() {
+				from := ytbx.InputFile{
+					Location:  "/ginkgo/compare/test/from",
+					Documents: multiDoc(`{"foo": "bar"}`),
+				}
+
+				to := ytbx.InputFile{
+					Location:  "/ginkgo/compare/test/to",
+					Documents: multiDoc(`{"list": [1, 2, 3]}`, `{"key": "value"}`),
+				}
+
+				result, err := dyff.CompareInputFiles(from, to)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Diffs).To(HaveLen(3))
+				Expect(result.Diffs[0].Details[0].Kind).To(Equal(dyff.REMOVAL))
+				Expect(result.Diffs[1].Details[0].Kind).To(Equal(dyff.ADDITION))
+				Expect(result.Diffs[2].Details[0].Kind).To(Equal(dyff.ADDITION))
+			})
+
+			It("should pair modified flow style documents and describe added or removed documents with their side", func() {
+				from := ytbx.InputFile{
+					Location:  "/ginkgo/compare/test/from",
+					Documents: multiDoc(`{"service": "web", "port": 80, "replicas": 2, "image": "nginx"}`, `{"foo": "bar"}`),
+				}
+
+				to := ytbx.InputFile{
+					Location:  "/ginkgo/compare/test/to",
+					Documents: multiDoc(`{"list": [1, 2, 3]}`, `{"service": "web", "port": 8080, "replicas": 2, "image": "nginx"}`, `{"key": "value"}`),
+				}
+
+				result, err := dyff.CompareInputFiles(from, to)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(result.Diffs).To(HaveLen(4))
+				Expect(result.Diffs[0]).To(BeSameDiffAs(singleDiff("#0/port", dyff.MODIFICATION, 80, 8080)))
+
+				Expect(result.Diffs[1].Details[0].Kind).To(Equal(dyff.REMOVAL))
+				Expect(result.Diffs[1].Path.RootDescription()).To(Equal("document #2 (from)"))
+				Expect(result.Diffs[2].Details[0].Kind).To(Equal(dyff.ADDITION))
+				Expect(result.Diffs[2].Path.RootDescription()).To(Equal("document #1 (to)"))
+				Expect(result.Diffs[3].Details[0].Kind).To(Equal(dyff.ADDITION))
+				Expect(result.Diffs[3].Path.RootDescription()).To(Equal("document #3 (to)"))
+			})
+		})
+
+		Context("input files containing complex objects with custom keys", func() {
+			It("reports no differences when the keys cannot be determined, but the entries only differ in order", func() {
+				from, to, err := ytbx.LoadFiles(assets("issues", "issue-243", "to.yml"), assets("issues", "issue-243", "from.yml"))
+				Expect(err).To(BeNil())
+				Expect(from).ToNot(BeNil())
+				Expect(to).ToNot(BeNil())
+
+				results, err := dyff.CompareInputFiles(from, to, dyff.IgnoreOrderChanges(true))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(results).ToNot(BeNil())
+				Expect(results.Diffs).To(HaveLen(0))
 			})
 
 			It("accurately reports no differences when keys are given", func() {
@@ -21,9 +21,9 @@
 package dyff
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"sort"
 	"strings"
 
 	"github.com/gonvenience/bunt"
@@ -31,7 +31,6 @@ import (
 	"github.com/gonvenience/text"
 	"github.com/gonvenience/ytbx"
 
-	"github.com/mitchellh/hashstructure"
 	yamlv3 "gopkg.in/yaml.v3"
 )
 
@@ -44,13 +43,52 @@ type compareSettings struct {
 	IgnoreWhitespaceChanges                  bool
 	KubernetesEntityDetection                bool
 	DetectRenames                            bool
+	DetectEntryRenames                       bool
 	MarshalJsonStrings                       bool
 	ChompBlockScalars                        bool
+	DetectEmbeddedDocuments                  bool
+	KubernetesQuantities                     bool
+	SemanticScalars                          bool
+	FloatTolerance                           float64
 	AdditionalIdentifiers                    []string
+	IdentifierRules                          []IdentifierRule
+	Schemas                                  *Schemas
+	PositionalLists                          bool
+	FuzzyListPairingThreshold                float64
+	ListOrderRules                           []ListOrderRule
+	DetectKeyOrderChanges                    bool
+	DetectMapEntryMoves                      bool
+	NullAsAbsent                             bool
+	EmptyAsAbsent                            bool
+	CompareComments                          bool
+	ResolveMergeKeys                         bool
+	ReportAnchorChanges                      bool
+	NormalizeTags                            bool
+	Concurrency                              int
+	MaxDepth                                 int
+	MaxNodes                                 int
+	MaxAliasExpansions                       int
+}
+
+// IdentifierRule defines the fields that identify the entries of lists, which
+// are located at paths that match the path pattern
+type IdentifierRule struct {
+	PathPattern string
+	Fields      []string
+}
+
+// ListOrderRule defines whether the order of the entries of lists, which are
+// located at paths that match the path pattern, is relevant (ordered list) or
+// not (set)
+type ListOrderRule struct {
+	PathPattern string
+	Ordered     bool
 }
 
 type compare struct {
+	ctx      context.Context
 	settings compareSettings
+	hashes   *nodeHashes
 }
 
 // AdditionalIdentifiers specifies additional identifiers that will be
@@ -61,6 +99,97 @@ func AdditionalIdentifiers(fieldNames ...string) CompareOption {
 	}
 }
 
+// IdentifierRules specifies which fields are used as the key for matching list
+// entries of lists at specific paths, where multiple fields can be combined to
+// identify an entry (e.g. `containerPort` and `protocol`). Rules are checked in
+// order and take precedence over the automatic identifier detection.
+func IdentifierRules(rules ...IdentifierRule) CompareOption {
+	return func(settings *compareSettings) {
+		settings.IdentifierRules = append(settings.IdentifierRules, rules...)
+	}
+}
+
+// ParseIdentifierRule parses an identifier rule from its text representation,
+// which is the path pattern and the comma separated list of fields, for example
+// `spec.template.spec.containers.*.ports=containerPort,protocol`
+func ParseIdentifierRule(rule string) (IdentifierRule, error) {
+	idx := strings.LastIndex(rule, "=")
+	if idx <= 0 || idx == len(rule)-1 {
+		return IdentifierRule{}, fmt.Errorf("invalid identifier rule %q, expected format is <path-pattern>=<field>[,<field>...]", rule)
+	}
+
+	var fields []string
+	for _, field := range strings.Split(rule[idx+1:], ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+
+	if len(fields) == 0 {
+		return IdentifierRule{}, fmt.Errorf("invalid identifier rule %q, no fields specified", rule)
+	}
+
+	return IdentifierRule{PathPattern: rule[:idx], Fields: fields}, nil
+}
+
+// ListOrderRules specifies which lists are sets, where order changes are
+// irrelevant, and which lists are ordered. Rules are checked in order and take
+// precedence over schemas and the global setting to ignore order changes.
+func ListOrderRules(rules ...ListOrderRule) CompareOption {
+	return func(settings *compareSettings) {
+		settings.ListOrderRules = append(settings.ListOrderRules, rules...)
+	}
+}
+
+// ParseListOrderRule parses a list order rule from its text representation,
+// which is the path pattern and either `set` or `ordered`, for example
+// `spec.template.spec.containers.*.env=set`
+func ParseListOrderRule(rule string) (ListOrderRule, error) {
+	idx := strings.LastIndex(rule, "=")
+	if idx <= 0 {
+		return ListOrderRule{}, fmt.Errorf("invalid list order rule %q, expected format is <path-pattern>=set|ordered", rule)
+	}
+
+	switch strings.TrimSpace(rule[idx+1:]) {
+	case "set":
+		return ListOrderRule{PathPattern: rule[:idx], Ordered: false}, nil
+
+	case "ordered":
+		return ListOrderRule{PathPattern: rule[:idx], Ordered: true}, nil
+
+	default:
+		return ListOrderRule{}, fmt.Errorf("invalid list order rule %q, expected either set or ordered", rule)
+	}
+}
+
+// PositionalLists enables comparing lists without identifiers entry by entry,
+// so that the report shows at which index entries were inserted, removed, or
+// modified, instead of showing all additions and removals at once
+func PositionalLists(value bool) CompareOption {
+	return func(settings *compareSettings) {
+		settings.PositionalLists = value
+	}
+}
+
+// FuzzyListPairing enables pairing removed and added entries of lists without
+// identifier based on the similarity of their content (between 0 and 1), so
+// that entries above the threshold are compared in detail. Zero disables it.
+func FuzzyListPairing(threshold float64) CompareOption {
+	return func(settings *compareSettings) {
+		settings.FuzzyListPairingThreshold = threshold
+	}
+}
+
+// KubernetesSchemas specifies schemas that declare the list types and keys of
+// lists in Kubernetes resources (`x-kubernetes-list-type` and
+// `x-kubernetes-list-map-keys`), which take precedence over the automatic
+// identifier detection
+func KubernetesSchemas(schemas *Schemas) CompareOption {
+	return func(settings *compareSettings) {
+		settings.Schemas = schemas
+	}
+}
+
 // NonStandardIdentifierGuessCountThreshold specifies how many list entries are
 // needed for the guess-the-identifier function to actually consider the key
 // name. Or in short, if the lists only contain two entries each, there are more
@@ -99,6 +228,118 @@ func DetectRenames(value bool) CompareOption {
 	}
 }
 
+// DetectEntryRenames enables the detection of renamed list entries and map
+// keys, which are paired with the removed entry based on the similarity of
+// their content and reported as a rename followed by the changes of the content
+func DetectEntryRenames(value bool) CompareOption {
+	return func(settings *compareSettings) {
+		settings.DetectEntryRenames = value
+	}
+}
+
+// DetectKeyOrderChanges enables the detection of changes of the order of the
+// keys in maps, which only considers keys that exist in both maps
+func DetectKeyOrderChanges(value bool) CompareOption {
+	return func(settings *compareSettings) {
+		settings.DetectKeyOrderChanges = value
+	}
+}
+
+// DetectMapEntryMoves enables the detection of map entries with a map or list
+// value that were moved to a different location in the same document, instead
+// of reporting a removal and an addition. List entries are not considered.
+func DetectMapEntryMoves(value bool) CompareOption {
+	return func(settings *compareSettings) {
+		settings.DetectMapEntryMoves = value
+	}
+}
+
+// NullAsAbsent treats null values like absent values, so that a map entry
+// with a null value and a missing map entry are considered to be equal
+func NullAsAbsent(value bool) CompareOption {
+	return func(settings *compareSettings) {
+		settings.NullAsAbsent = value
+	}
+}
+
+// EmptyAsAbsent treats empty maps, empty lists, and empty strings like absent
+// values, so that for example `annotations: {}` and a missing `annotations`
+// map entry are considered to be equal
+func EmptyAsAbsent(value bool) CompareOption {
+	return func(settings *compareSettings) {
+		settings.EmptyAsAbsent = value
+	}
+}
+
+// CompareComments enables the comparison of the head, line, and foot comments,
+// which are reported at the path of the node that the comment belongs to
+func CompareComments(value bool) CompareOption {
+	return func(settings *compareSettings) {
+		settings.CompareComments = value
+	}
+}
+
+// ResolveMergeKeys enables expanding merge keys (`<<: *base`) into the map
+// entries they stand for, so that maps are compared by their effective content
+func ResolveMergeKeys(value bool) CompareOption {
+	return func(settings *compareSettings) {
+		settings.ResolveMergeKeys = value
+	}
+}
+
+// ReportAnchorChanges enables reporting structural changes of anchors, aliases,
+// and merge keys (if resolved), which are reported separately from data changes
+func ReportAnchorChanges(value bool) CompareOption {
+	return func(settings *compareSettings) {
+		settings.ReportAnchorChanges = value
+	}
+}
+
+// NormalizeTags enables translating nodes with custom tags using the registered
+// tag normalizers (see RegisterTagNormalizer) in case the tags differ, so that
+// for example `!Ref X` and `{Ref: X}` are considered to be equal
+func NormalizeTags(value bool) CompareOption {
+	return func(settings *compareSettings) {
+		settings.NormalizeTags = value
+	}
+}
+
+// Concurrency sets the number of documents that are compared in parallel, a
+// value below one uses the number of available CPUs. The order of the
+// differences in the report is the same regardless of the concurrency.
+func Concurrency(value int) CompareOption {
+	return func(settings *compareSettings) {
+		settings.Concurrency = value
+	}
+}
+
+// MaxDepth limits the nesting depth of the input documents, a value of zero
+// means no limit. Inputs that exceed the limit result in a LimitError.
+func MaxDepth(value int) CompareOption {
+	return func(settings *compareSettings) {
+		settings.MaxDepth = value
+	}
+}
+
+// MaxNodes limits the number of nodes of an input file (with expanded aliases),
+// a value of zero means no limit. Inputs that exceed the limit result in a
+// LimitError.
+func MaxNodes(value int) CompareOption {
+	return func(settings *compareSettings) {
+		settings.MaxNodes = value
+	}
+}
+
+// MaxAliasExpansions limits the number of aliases in an input file, where
+// aliases in anchored content count every time the content is referenced, a
+// value of zero means no limit. Inputs that exceed the limit, for example the
+// "billion laughs" attack, result in a LimitError.
+func MaxAliasExpansions(value int) CompareOption {
+	return func(settings *compareSettings) {
+		settings.MaxAliasExpansions = value
+	}
+}
+
 func MarshalJsonStrings(value bool) CompareOption {
 	return func(settings *compareSettings) {
 		settings.MarshalJsonStrings = value
@@ -111,17 +352,56 @@ func ChompBlockScalars(value bool) CompareOption {
 	}
 }
 
-// CompareInputFiles is one of the convenience main entry points for comparing
-// objects. In this case the representation of an input file, which might
-// contain multiple documents. It returns a report with the list of differences.
-func CompareInputFiles(from ytbx.InputFile, to ytbx.InputFile, compareOptions ...CompareOption) (Report, error) {
-	// initialize the comparator with the tool defaults
-	cmpr := compare{
+// DetectEmbeddedDocuments enables parsing string values that contain JSON,
+// YAML, or TOML documents to compare them structurally instead of as text
+func DetectEmbeddedDocuments(value bool) CompareOption {
+	return func(settings *compareSettings) {
+		settings.DetectEmbeddedDocuments = value
+	}
+}
+
+// KubernetesQuantities enables comparing Kubernetes resource quantities (for
+// example in `resources.requests`) by their value, so that `1Gi` and `1024Mi`
+// are considered to be equal. Only documents with `apiVersion` and `kind` are
+// considered to be Kubernetes resources.
+func KubernetesQuantities(value bool) CompareOption {
+	return func(settings *compareSettings) {
+		settings.KubernetesQuantities = value
+	}
+}
+
+// SemanticScalars enables comparing numbers, timestamps, and booleans by their
+// value instead of their notation, so that `1.0` and `1`, or `0x10` and `16` are
+// considered to be equal and are only reported as a format change with the note
+// that the values are equivalent
+func SemanticScalars(value bool) CompareOption {
+	return func(settings *compareSettings) {
+		settings.SemanticScalars = value
+	}
+}
+
+// FloatTolerance specifies the maximum difference of two numbers to still be
+// considered equal, only used in combination with SemanticScalars. Numbers
+// that differ within the tolerance are reported with a note, just like values
+// that are only written differently, but do not count as a change.
+func FloatTolerance(tolerance float64) CompareOption {
+	return func(settings *compareSettings) {
+		settings.FloatTolerance = tolerance
+	}
+}
+
+// newCompare creates a comparator with the tool defaults and the provided
+// compare options applied
+func newCompare(ctx context.Context, compareOptions ...CompareOption) *compare {
+	cmpr := &compare{
+		ctx: ctx,
 		settings: compareSettings{
 			NonStandardIdentifierGuessCountThreshold: 3,
 			IgnoreOrderChanges:                       false,
 			KubernetesEntityDetection:                true,
+			Concurrency:                              1,
 		},
+		hashes: newNodeHashes(),
 	}
 
 	// apply the optional compare options provided to this function call
@@ -129,6 +409,33 @@ func CompareInputFiles(from ytbx.InputFile, to ytbx.InputFile, compareOptions ..
 		compareOption(&cmpr.settings)
 	}
 
+	return cmpr
+}
+
+// CompareInputFiles is one of the convenience main entry points for comparing
+// objects. In this case the representation of an input file, which might
+// contain multiple documents. It returns a report with the list of differences.
+func CompareInputFiles(from ytbx.InputFile, to ytbx.InputFile, compareOptions ...CompareOption) (Report, error) {
+	return CompareInputFilesContext(context.Background(), from, to, compareOptions...)
+}
+
+// CompareInputFilesContext compares input files like CompareInputFiles, but
+// stops in case the context is cancelled. In combination with the options
+// MaxDepth, MaxNodes, and MaxAliasExpansions, it is suitable for input from
+// untrusted sources. Unexpected failures are returned as a PanicError.
+func CompareInputFilesContext(ctx context.Context, from ytbx.InputFile, to ytbx.InputFile, compareOptions ...CompareOption) (report Report, err error) {
+	defer recoverPanic(&err)
+
+	cmpr := newCompare(ctx, compareOptions...)
+
+	if err := cmpr.checkLimits(from); err != nil {
+		return Report{}, err
+	}
+
+	if err := cmpr.checkLimits(to); err != nil {
+		return Report{}, err
+	}
+
 	// in case Kubernetes mode is enabled, try to compare documents in the YAML
 	// file by their names rather than just by the order of the documents
 	if cmpr.settings.KubernetesEntityDetection {
@@ -136,7 +443,7 @@ func CompareInputFiles(from ytbx.InputFile, to ytbx.InputFile, compareOptions ..
 		var fromNames, toNames []string
 
 		for i := range from.Documents {
-			if entry := from.Documents[i]; !isEmptyDocument(entry) {
+			if entry := from.Documents[i]; !isEmptyDocument(entry) && !cmpr.isAbsent(entry) {
 				fromDocs = append(fromDocs, entry)
 				if name, err := k8sItem.Name(entry.Content[0]); err == nil {
 					fromNames = append(fromNames, name)
@@ -145,7 +452,7 @@ func CompareInputFiles(from ytbx.InputFile, to ytbx.InputFile, compareOptions ..
 		}
 
 		for i := range to.Documents {
-			if entry := to.Documents[i]; !isEmptyDocument(entry) {
+			if entry := to.Documents[i]; !isEmptyDocument(entry) && !cmpr.isAbsent(entry) {
 				toDocs = append(toDocs, entry)
 				if name, err := k8sItem.Name(entry.Content[0]); err == nil {
 					toNames = append(toNames, name)
@@ -166,36 +473,135 @@ func CompareInputFiles(from ytbx.InputFile, to ytbx.InputFile, compareOptions ..
 			if err != nil {
 				return Report{}, fmt.Errorf("comparing Kubernetes resources: %w", err)
 			}
+
+			result, err = cmpr.moves(result)
+			if err != nil {
+				return Report{}, err
+			}
 			return Report{from, to, result}, nil
 		}
 	}
 
+	// in case the number of documents differ, try to align the documents of both
+	// input files to find out which documents were added or removed
 	if len(from.Documents) != len(to.Documents) {
-		return Report{}, fmt.Errorf("comparing YAMLs with a different number of documents is currently not supported")
+		result, err := cmpr.documentSequences(from, to)
+		if err != nil {
+			return Report{}, fmt.Errorf("comparing YAMLs with a different number of documents: %w", err)
+		}
+
+		result, err = cmpr.moves(result)
+		if err != nil {
+			return Report{}, err
+		}
+		return Report{from, to, result}, nil
 	}
 
-	var result []Diff
+	pairs := make([]documentPair, len(from.Documents))
 	for idx := range from.Documents {
-		diffs, err := cmpr.objects(
-			ytbx.Path{
+		pairs[idx] = documentPair{
+			path: ytbx.Path{
 				Root:        &from,
 				DocumentIdx: idx,
 			},
-			from.Documents[idx],
-			to.Documents[idx],
-		)
-
-		if err != nil {
-			return Report{}, err
+			from: from.Documents[idx],
+			to:   to.Documents[idx],
 		}
+	}
 
-		result = append(result, diffs...)
+	result, err := cmpr.documentPairs(pairs)
+	if err != nil {
+		return Report{}, err
+	}
+
+	result, err = cmpr.moves(result)
+	if err != nil {
+		return Report{}, err
 	}
 
 	return Report{from, to, result}, nil
 }
 
+// CompareNodes is a convenience entry point for comparing YAML nodes without
+// having to wrap them into input files. The nodes do not have to be documents,
+// a nil node is considered to be an empty input. The report uses `from` and
+// `to` as the locations of the inputs, the paths of the differences refer to
+// the first document.
+func CompareNodes(from, to *yamlv3.Node, compareOptions ...CompareOption) (Report, error) {
+	return CompareInputFiles(nodeInputFile("from", from), nodeInputFile("to", to), compareOptions...)
+}
+
+// CompareValues is a convenience entry point for comparing Go values, like
+// structs, maps, or slices. The values are translated into YAML nodes using
+// the YAML encoding rules (e.g. `yaml` struct tags) and compared using
+// CompareNodes.
+func CompareValues(from, to any, compareOptions ...CompareOption) (Report, error) {
+	fromNode, err := valueNode(from)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to translate from value: %w", err)
+	}
+
+	toNode, err := valueNode(to)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to translate to value: %w", err)
+	}
+
+	return CompareNodes(fromNode, toNode, compareOptions...)
+}
+
+func nodeInputFile(location string, node *yamlv3.Node) ytbx.InputFile {
+	switch {
+	case node == nil:
+		return ytbx.InputFile{Location: location}
+
+	case node.Kind == yamlv3.DocumentNode:
+		return ytbx.InputFile{Location: location, Documents: []*yamlv3.Node{node}}
+
+	default:
+		return ytbx.InputFile{
+			Location: location,
+			Documents: []*yamlv3.Node{{
+				Kind:    yamlv3.DocumentNode,
+				Content: []*yamlv3.Node{node},
+			}},
+		}
+	}
+}
+
+func valueNode(value any) (result *yamlv3.Node, err error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	// the encoder panics for types that cannot be represented in YAML
+	defer func() {
+		if cause := recover(); cause != nil {
+			result, err = nil, fmt.Errorf("%v", cause)
+		}
+	}()
+
+	var node yamlv3.Node
+	if err := node.Encode(value); err != nil {
+		return nil, err
+	}
+
+	return &node, nil
+}
+
 func (compare *compare) objects(path ytbx.Path, from *yamlv3.Node, to *yamlv3.Node) ([]Diff, error) {
+	if err := compare.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	diffs, err := compare.nodes(path, from, to)
+	if err != nil || !compare.settings.CompareComments || from == nil || to == nil {
+		return diffs, err
+	}
+
+	return withComments(path, from, to, diffs), nil
+}
+
+func (compare *compare) nodes(path ytbx.Path, from *yamlv3.Node, to *yamlv3.Node) ([]Diff, error) {
 	switch {
 	case from == nil && to == nil:
 		return []Diff{}, nil
@@ -210,6 +616,32 @@ func (compare *compare) objects(path ytbx.Path, from *yamlv3.Node, to *yamlv3.No
 			}},
 		}}, nil
 
+	case compare.isAbsent(from) && compare.isAbsent(to):
+		return []Diff{}, nil
+	}
+
+	// values with different tags can still be equivalent, e.g. the short form
+	// and the long form of a function, same tags are compared as they are
+	if from.Tag != to.Tag {
+		from, to = compare.normalizeTag(from), compare.normalizeTag(to)
+	}
+
+	// quantities and other scalars compared by value can be written using
+	// different types, therefore they need to be compared before the types
+	// (tags) are checked
+	if compare.settings.KubernetesQuantities {
+		if diffs, ok := compare.quantities(path, from, to); ok {
+			return diffs, nil
+		}
+	}
+
+	if compare.settings.SemanticScalars {
+		if diffs, ok := compare.semanticScalars(path, from, to); ok {
+			return diffs, nil
+		}
+	}
+
+	switch {
 	case (from.Kind != to.Kind) || (from.Tag != to.Tag):
 		return []Diff{{
 			&path,
@@ -231,6 +663,9 @@ func (compare *compare) nonNilSameKindNodes(path ytbx.Path, from *yamlv3.Node, t
 	switch from.Kind {
 	case yamlv3.DocumentNode:
 		diffs, err = compare.objects(path, from.Content[0], to.Content[0])
+		if err == nil {
+			diffs = compare.withAnchorChanges(path, from.Content[0], to.Content[0], diffs)
+		}
 
 	case yamlv3.MappingNode:
 		diffs, err = compare.mappingNodes(path, from, to)
@@ -273,8 +708,6 @@ func (compare *compare) nonNilSameKindNodes(path ytbx.Path, from *yamlv3.Node, t
 }
 
 func (compare *compare) documentNodes(from, to ytbx.InputFile) ([]Diff, error) {
-	var result []Diff
-
 	type doc struct {
 		node *yamlv3.Node
 		idx  int
@@ -311,21 +744,17 @@ func (compare *compare) documentNodes(from, to ytbx.InputFile) ([]Diff, error) {
 
 	var removals []doc
 	var additions []doc
+	var pairs []documentPair
 
 	for _, name := range fromNames {
 		var fromItem = fromLookUpMap[name]
 		if toItem, ok := toLookUpMap[name]; ok {
 			// `from` and `to` contain the same `key` -> require comparison
-			diffs, err := compare.objects(
-				ytbx.Path{Root: &from, DocumentIdx: fromItem.idx},
-				followAlias(fromItem.node),
-				followAlias(toItem.node),
-			)
-			if err != nil {
-				return nil, err
-			}
-
-			result = append(result, diffs...)
+			pairs = append(pairs, documentPair{
+				path: ytbx.Path{Root: &from, DocumentIdx: fromItem.idx},
+				from: followAlias(fromItem.node),
+				to:   followAlias(toItem.node),
+			})
 		} else {
 			// `from` contain the `key`, but `to` does not -> removal
 			removals = append(removals, fromItem)
@@ -341,6 +770,11 @@ func (compare *compare) documentNodes(from, to ytbx.InputFile) ([]Diff, error) {
 		}
 	}
 
+	result, err := compare.documentPairs(pairs)
+	if err != nil {
+		return nil, err
+	}
+
 	candidateName := func(mappingNode *yamlv3.Node) string {
 		name, _ := k8sItem.Name(mappingNode)
 		return name
@@ -369,10 +803,11 @@ func (compare *compare) documentNodes(from, to ytbx.InputFile) ([]Diff, error) {
 		}
 	}
 
-	// Push rename detection results
+	// Push rename detection results, the paths refer to the `from` documents
+	// like the paths of all other compared documents
 	for _, modified := range changes.ModifiedPairs() {
 		diffs, err := compare.objects(
-			*modified.To.Path,
+			*modified.From.Path,
 			followAlias(modified.From.Doc),
 			followAlias(modified.To.Doc),
 		)
@@ -437,23 +872,40 @@ func (compare *compare) mappingNodes(path ytbx.Path, from *yamlv3.Node, to *yaml
 	removals := []*yamlv3.Node{}
 	additions := []*yamlv3.Node{}
 
+	// changes of the merge keys are structural changes, the merged entries are
+	// compared like any other map entry
+	var anchorChanges []Detail
+	if compare.settings.ResolveMergeKeys {
+		if compare.settings.ReportAnchorChanges {
+			anchorChanges = mergeKeyChanges(from, to)
+		}
+
+		from, to = resolveMergeKeys(from), resolveMergeKeys(to)
+	}
+
 	for i := 0; i < len(from.Content); i += 2 {
 		key, fromItem := from.Content[i], from.Content[i+1]
-		if toItem, ok := findValueByKey(to, key.Value); ok {
+		if toKey, toItem, ok := findEntry(to, key.Value); ok {
+			keyPath := ytbx.NewPathWithNamedElement(path, key.Value)
+			fromValue, toValue := followAlias(fromItem), followAlias(toItem)
+			if compare.settings.CompareComments {
+				fromValue, toValue = withKeyComments(key, fromValue), withKeyComments(toKey, toValue)
+			}
+
 			// `from` and `to` contain the same `key` -> require comparison
 			diffs, err := compare.objects(
-				ytbx.NewPathWithNamedElement(path, key.Value),
-				followAlias(fromItem),
-				followAlias(toItem),
+				keyPath,
+				fromValue,
+				toValue,
 			)
 
 			if err != nil {
 				return nil, err
 			}
 
-			result = append(result, diffs...)
+			result = append(result, compare.withAnchorChanges(keyPath, fromItem, toItem, diffs)...)
 
-		} else {
+		} else if !compare.isAbsent(fromItem) {
 			// `from` contain the `key`, but `to` does not -> removal
 			removals = append(removals, key, fromItem)
 		}
@@ -461,14 +913,26 @@ func (compare *compare) mappingNodes(path ytbx.Path, from *yamlv3.Node, to *yaml
 
 	for i := 0; i < len(to.Content); i += 2 {
 		key, toItem := to.Content[i], to.Content[i+1]
-		if _, ok := findValueByKey(from, key.Value); !ok {
+		if _, ok := findValueByKey(from, key.Value); !ok && !compare.isAbsent(toItem) {
 			// `to` contains a `key` that `from` does not have -> addition
 			additions = append(additions, key, toItem)
 		}
 	}
 
+	// Find keys that were renamed, but have a similar (complex) value
+	renames, removals, additions, err := compare.renamedMappingKeys(path, removals, additions)
+	if err != nil {
+		return nil, err
+	}
+
+	result = append(result, renames...)
+
 	diff := Diff{Path: &path, Details: []Detail{}}
 
+	if compare.settings.DetectKeyOrderChanges {
+		diff.Details = append(diff.Details, findOrderChangesInMappings(from, to)...)
+	}
+
 	if len(removals) > 0 {
 		diff.Details = append(diff.Details,
 			Detail{
@@ -501,6 +965,10 @@ func (compare *compare) mappingNodes(path ytbx.Path, from *yamlv3.Node, to *yaml
 		result = append([]Diff{diff}, result...)
 	}
 
+	if len(anchorChanges) > 0 {
+		result = append([]Diff{{Path: &path, Details: anchorChanges}}, result...)
+	}
+
 	return result, nil
 }
 
@@ -510,23 +978,49 @@ func (compare *compare) sequenceNodes(path ytbx.Path, from *yamlv3.Node, to *yam
 		return []Diff{}, nil
 	}
 
+	if identifier := compare.listIdentifier(path, from, to); identifier != nil {
+		return compare.namedEntryLists(path, identifier, from, to)
+	}
+
+	// in any other case, compare lists as simple lists by relying on hashes
+	return compare.simpleLists(path, from, to)
+}
+
+// listIdentifier returns the identifier that is used to match the entries of
+// both lists, or nil in case the lists have to be compared as simple lists
+func (compare *compare) listIdentifier(path ytbx.Path, from *yamlv3.Node, to *yamlv3.Node) listItemIdentifier {
+	// check if a configured identifier rule applies to the list
+	if identifier, err := compare.getIdentifierFromRules(path, from, to); err == nil {
+		return identifier
+	}
+
+	// check if a schema declares how the entries of the list are identified
+	switch listType, listMapKeys := compare.settings.Schemas.lookup(path); listType {
+	case "map":
+		if identifier, err := compare.getIdentifierFromFields(listMapKeys, from, to); err == nil {
+			return identifier
+		}
+
+	case "set", "atomic":
+		return nil
+	}
+
 	// check if a known identifier (e.g. name, or id) can be used
 	if identifier, err := compare.getIdentifierFromNamedLists(from, to); err == nil {
-		return compare.namedEntryLists(path, identifier, from, to)
+		return identifier
 	}
 
 	// check if there is a field in all entries that could serve as an identifier
 	if identifier := compare.getNonStandardIdentifierFromNamedLists(from, to); identifier != nil {
-		return compare.namedEntryLists(path, identifier, from, to)
+		return identifier
 	}
 
 	// check if Kubernetes resource fields can be used to identify items
 	if identifier, err := compare.getIdentifierFromKubernetesEntityList(from, to); err == nil {
-		return compare.namedEntryLists(path, identifier, from, to)
+		return identifier
 	}
 
-	// in any other case, compare lists as simple lists by relying on hashes
-	return compare.simpleLists(path, from, to)
+	return nil
 }
 
 func (compare *compare) simpleLists(path ytbx.Path, from *yamlv3.Node, to *yamlv3.Node) ([]Diff, error) {
@@ -539,25 +1033,56 @@ func (compare *compare) simpleLists(path ytbx.Path, from *yamlv3.Node, to *yamlv
 	// Special case if both lists only contain one entry, then directly compare
 	// the two entries with each other
 	if fromLength == 1 && toLength == 1 {
-		return compare.objects(
-			ytbx.NewPathWithIndexedListElement(path, 0),
+		entryPath := ytbx.NewPathWithIndexedListElement(path, 0)
+		diffs, err := compare.objects(
+			entryPath,
 			followAlias(from.Content[0]),
 			followAlias(to.Content[0]),
 		)
+		if err != nil {
+			return nil, err
+		}
+
+		return compare.withAnchorChanges(entryPath, from.Content[0], to.Content[0], diffs), nil
+	}
+
+	// Compare entry by entry if configured, unless the order is irrelevant
+	if compare.settings.PositionalLists && !compare.ignoreOrderChangesAt(path) {
+		return compare.positionalLists(path, from, to)
+	}
+
+	fromLookup, err := compare.createLookUpMap(path, from)
+	if err != nil {
+		return nil, err
 	}
 
-	fromLookup := compare.createLookUpMap(from)
-	toLookup := compare.createLookUpMap(to)
+	toLookup, err := compare.createLookUpMap(path, to)
+	if err != nil {
+		return nil, err
+	}
 
-	// Fill two lists with the hashes of the entries of each list
-	fromCommon := make([]*yamlv3.Node, 0, fromLength)
-	toCommon := make([]*yamlv3.Node, 0, toLength)
+	// Entries that exist in both lists can still differ in their comments,
+	// anchors, and aliases
+	var unchangedEntries []Diff
+	unchangedPairs := map[uint64]int{}
+	compareUnchanged := compare.settings.CompareComments || compare.settings.ReportAnchorChanges
 
 	for idxPos, fromValue := range from.Content {
-		hash := compare.calcNodeHash(fromValue)
+		hash := compare.calcNodeHash(anyListEntry(path), fromValue)
 		_, ok := toLookup[hash]
-		if ok {
-			fromCommon = append(fromCommon, fromValue)
+
+		if ok && compareUnchanged && unchangedPairs[hash] < len(toLookup[hash]) {
+			diffs, err := compare.unchangedEntryChanges(
+				ytbx.NewPathWithIndexedListElement(path, idxPos),
+				fromValue,
+				to.Content[toLookup[hash][unchangedPairs[hash]]],
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			unchangedEntries = append(unchangedEntries, diffs...)
+			unchangedPairs[hash]++
 		}
 
 		switch {
@@ -568,7 +1093,7 @@ func (compare *compare) simpleLists(path ytbx.Path, from *yamlv3.Node, to *yamlv
 		case len(fromLookup[hash]) > len(toLookup[hash]):
 			// `from` entry exists in `to` list, but there are duplicates and
 			// the number of duplicates is smaller
-			if !compare.hasEntry(removals, from.Content[idxPos]) {
+			if !compare.hasEntry(path, removals, from.Content[idxPos]) {
 				for i := 0; i < len(fromLookup[hash])-len(toLookup[hash]); i++ {
 					removals = append(removals, from.Content[idxPos])
 				}
@@ -577,11 +1102,8 @@ func (compare *compare) simpleLists(path ytbx.Path, from *yamlv3.Node, to *yamlv
 	}
 
 	for idxPos, toValue := range to.Content {
-		hash := compare.calcNodeHash(toValue)
+		hash := compare.calcNodeHash(anyListEntry(path), toValue)
 		_, ok := fromLookup[hash]
-		if ok {
-			toCommon = append(toCommon, toValue)
-		}
 
 		switch {
 		case !ok:
@@ -591,7 +1113,7 @@ func (compare *compare) simpleLists(path ytbx.Path, from *yamlv3.Node, to *yamlv
 		case len(fromLookup[hash]) < len(toLookup[hash]):
 			// `to` entry exists in `from` list, but there are duplicates and
 			// the number of duplicates is increased
-			if !compare.hasEntry(additions, to.Content[idxPos]) {
+			if !compare.hasEntry(path, additions, to.Content[idxPos]) {
 				for i := 0; i < len(toLookup[hash])-len(fromLookup[hash]); i++ {
 					additions = append(additions, to.Content[idxPos])
 				}
@@ -599,12 +1121,41 @@ func (compare *compare) simpleLists(path ytbx.Path, from *yamlv3.Node, to *yamlv
 		}
 	}
 
+	// Pair remaining entries that are similar to compare them in detail
+	modifications, paired, removals, additions, err := compare.similarListEntries(path, from, removals, additions)
+	if err != nil {
+		return nil, err
+	}
+
 	var orderChanges []Detail
-	if !compare.settings.IgnoreOrderChanges {
-		orderChanges = compare.findOrderChangesInSimpleList(fromCommon, toCommon)
+	if !compare.ignoreOrderChangesAt(path) {
+		// Fill two lists with the entries that are common in both lists, where
+		// entries paired by similarity count as common entries, too
+		pairedFrom := make(map[*yamlv3.Node]struct{}, len(paired))
+		for _, fromValue := range paired {
+			pairedFrom[fromValue] = struct{}{}
+		}
+
+		fromCommon := make([]*yamlv3.Node, 0, fromLength)
+		for _, fromValue := range from.Content {
+			_, ok := toLookup[compare.calcNodeHash(anyListEntry(path), fromValue)]
+			if _, isPaired := pairedFrom[fromValue]; ok || isPaired {
+				fromCommon = append(fromCommon, fromValue)
+			}
+		}
+
+		toCommon := make([]*yamlv3.Node, 0, toLength)
+		for _, toValue := range to.Content {
+			_, ok := fromLookup[compare.calcNodeHash(anyListEntry(path), toValue)]
+			if _, isPaired := paired[toValue]; ok || isPaired {
+				toCommon = append(toCommon, toValue)
+			}
+		}
+
+		orderChanges = compare.findOrderChangesInSimpleList(path, fromCommon, toCommon, paired)
 	}
 
-	return packChangesAndAddToResult([]Diff{}, path, orderChanges, additions, removals)
+	return packChangesAndAddToResult(append(unchangedEntries, modifications...), path, orderChanges, additions, removals)
 }
 
 func (compare *compare) namedEntryLists(path ytbx.Path, identifier listItemIdentifier, from *yamlv3.Node, to *yamlv3.Node) ([]Diff, error) {
@@ -618,25 +1169,35 @@ func (compare *compare) namedEntryLists(path ytbx.Path, identifier listItemIdent
 	fromNames := make([]string, 0, fromLength)
 	toNames := make([]string, 0, fromLength)
 
+	// Index both lists by the names of the entries once, so that looking up an
+	// entry by name does not require to go through the whole list every time
+	fromEntryNames, fromIndex, err := entriesByName(identifier, from)
+	if err != nil {
+		return nil, err
+	}
+
+	toEntryNames, toIndex, err := entriesByName(identifier, to)
+	if err != nil {
+		return nil, err
+	}
+
 	// Find entries that are common to both lists to compare them separately, and
 	// find entries that are only in from, but not to and are therefore removed
-	for _, fromEntry := range from.Content {
-		name, err := identifier.Name(fromEntry)
-		if err != nil {
-			return nil, fmt.Errorf("failed to identify name: %w", err)
-		}
+	for i, fromEntry := range from.Content {
+		name := fromEntryNames[i]
 
-		if toEntry, err := identifier.FindNodeByName(to, name); err == nil {
+		if toEntry, ok := toIndex[name]; ok {
 			// `from` and `to` have the same entry identified by identifier and name -> require comparison
+			entryPath := newPathWithListItem(path, identifier, name)
 			diffs, err := compare.objects(
-				ytbx.NewPathWithNamedListElement(path, identifier, name),
+				entryPath,
 				followAlias(fromEntry),
 				followAlias(toEntry),
 			)
 			if err != nil {
 				return nil, err
 			}
-			result = append(result, diffs...)
+			result = append(result, compare.withAnchorChanges(entryPath, fromEntry, toEntry, diffs)...)
 			fromNames = append(fromNames, name)
 
 		} else {
@@ -646,13 +1207,10 @@ func (compare *compare) namedEntryLists(path ytbx.Path, identifier listItemIdent
 	}
 
 	// Find entries that are only in to, but not from and are therefore added
-	for _, toEntry := range to.Content {
-		name, err := identifier.Name(toEntry)
-		if err != nil {
-			return nil, fmt.Errorf("failed to identify name: %w", err)
-		}
+	for i, toEntry := range to.Content {
+		name := toEntryNames[i]
 
-		if _, err := identifier.FindNodeByName(from, name); err == nil {
+		if _, ok := fromIndex[name]; ok {
 			// `to` and `from` have the same entry identified by identifier and name (comparison already covered by previous range)
 			toNames = append(toNames, name)
 
@@ -662,20 +1220,73 @@ func (compare *compare) namedEntryLists(path ytbx.Path, identifier listItemIdent
 		}
 	}
 
+	// Find entries that were renamed, but are otherwise similar
+	renames, removals, additions, err := compare.renamedListEntries(path, identifier, removals, additions)
+	if err != nil {
+		return nil, err
+	}
+
+	result = append(result, renames...)
+
 	var orderChanges []Detail
-	if !compare.settings.IgnoreOrderChanges {
+	if !compare.ignoreOrderChangesAt(path) {
 		orderChanges = findOrderChangesInNamedEntryLists(fromNames, toNames)
 	}
 
 	return packChangesAndAddToResult(result, path, orderChanges, additions, removals)
 }
 
+// entriesByName returns the names of the entries of the list and an index to
+// look up entries by name, in case of duplicate names the first entry is used
+func entriesByName(identifier listItemIdentifier, list *yamlv3.Node) ([]string, map[string]*yamlv3.Node, error) {
+	names := make([]string, len(list.Content))
+	index := make(map[string]*yamlv3.Node, len(list.Content))
+	for i, entry := range list.Content {
+		name, err := identifier.Name(entry)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to identify name: %w", err)
+		}
+
+		names[i] = name
+		if _, ok := index[name]; !ok {
+			index[name] = entry
+		}
+	}
+
+	return names, index, nil
+}
+
 func (compare *compare) nodeValues(path ytbx.Path, from *yamlv3.Node, to *yamlv3.Node) ([]Diff, error) {
+	// Strings that contain structured documents (e.g. configuration files in
+	// a ConfigMap) are compared as nodes, the path continues inside of them
+	if compare.settings.DetectEmbeddedDocuments && from.Value != to.Value {
+		if fromDoc, toDoc, ok := embeddedDocuments(from, to); ok {
+			location := fmt.Sprintf("embedded document at %s", path.String())
+			if err := compare.checkDocumentLimits(location, fromDoc, toDoc); err != nil {
+				return nil, err
+			}
+
+			diffs, err := compare.objects(path, fromDoc, toDoc)
+			if err != nil {
+				return nil, err
+			}
+
+			// the outermost string is the embedding in case of nested documents
+			for i := range diffs {
+				for j := range diffs[i].Details {
+					diffs[i].Details[j].Embedding = &path
+				}
+			}
+
+			return diffs, nil
+		}
+	}
+
 	fromValue := from.Value
 	toValue := to.Value
 	// Marshaling strings to Json removes formatting differences as the cause.
-	// This is a cheap way to do this
-	// TODO: Compare Json strings as nodes to be able to do a per-line comparison
+	// This is a cheap way to do this, see DetectEmbeddedDocuments for a
+	// structural comparison of the content
 	if compare.settings.MarshalJsonStrings {
 		var fromJson, toJson interface{}
 		// Actually just attempt to marshal the strings to json to fix any formatting diffs. Then can do a string comparison. That's fine.
@@ -709,6 +1320,52 @@ func (compare *compare) nodeValues(path ytbx.Path, from *yamlv3.Node, to *yamlv3
 	return nil, nil
 }
 
+// embeddedDocuments returns the parsed documents of both string nodes, but only
+// if both contain a JSON, YAML, or TOML document of the same type (map or list)
+func embeddedDocuments(from *yamlv3.Node, to *yamlv3.Node) (*yamlv3.Node, *yamlv3.Node, bool) {
+	fromDoc, ok := embeddedDocument(from.Value)
+	if !ok {
+		return nil, nil, false
+	}
+
+	toDoc, ok := embeddedDocument(to.Value)
+	if !ok {
+		return nil, nil, false
+	}
+
+	if fromDoc.Content[0].Kind != toDoc.Content[0].Kind {
+		return nil, nil, false
+	}
+
+	return fromDoc, toDoc, true
+}
+
+func embeddedDocument(value string) (*yamlv3.Node, bool) {
+	// Only consider JSON style, or multi-line strings, since almost every
+	// single-line text would be a valid YAML document
+	trimmed := strings.TrimSpace(value)
+	if len(trimmed) == 0 || (!strings.ContainsAny(trimmed[:1], "{[") && !strings.Contains(trimmed, "\n")) {
+		return nil, false
+	}
+
+	documents, err := ytbx.LoadDocuments([]byte(trimmed))
+	if err != nil || len(documents) != 1 {
+		return nil, false
+	}
+
+	document := documents[0]
+	if document.Kind != yamlv3.DocumentNode || len(document.Content) != 1 {
+		return nil, false
+	}
+
+	switch document.Content[0].Kind {
+	case yamlv3.MappingNode, yamlv3.SequenceNode:
+		return document, true
+	}
+
+	return nil, false
+}
+
 func (compare *compare) boolValues(path ytbx.Path, from *yamlv3.Node, to *yamlv3.Node) ([]Diff, error) {
 	boolFrom, err := toBool(from.Value)
 	if err != nil {
@@ -751,11 +1408,21 @@ func toBool(input string) (bool, error) {
 	return false, fmt.Errorf("not a valid boolean value: '%s'", input)
 }
 
-func (compare *compare) findOrderChangesInSimpleList(fromCommon, toCommon []*yamlv3.Node) []Detail {
+func (compare *compare) findOrderChangesInSimpleList(path ytbx.Path, fromCommon, toCommon []*yamlv3.Node, paired map[*yamlv3.Node]*yamlv3.Node) []Detail {
+	// Entries of the to list that were paired by similarity are identified by
+	// the entry of the from list they were paired with
+	identity := func(toValue *yamlv3.Node) *yamlv3.Node {
+		if fromValue, ok := paired[toValue]; ok {
+			return fromValue
+		}
+
+		return toValue
+	}
+
 	// Try to find order changes ...
 	if len(fromCommon) == len(toCommon) {
 		for idx := range fromCommon {
-			if compare.calcNodeHash(fromCommon[idx]) != compare.calcNodeHash(toCommon[idx]) {
+			if compare.calcNodeHash(anyListEntry(path), fromCommon[idx]) != compare.calcNodeHash(anyListEntry(path), identity(toCommon[idx])) {
 				return []Detail{{
 					Kind: ORDERCHANGE,
 					From: &yamlv3.Node{Kind: yamlv3.SequenceNode, Content: fromCommon},
@@ -771,10 +1438,10 @@ func (compare *compare) findOrderChangesInSimpleList(fromCommon, toCommon []*yam
 // hasEntry returns whether the given node is in the provided list. Not exactly
 // a fast or efficient way to verify that a node is already in a list, but
 // given that this should rarely be used it is ok for now.
-func (compare *compare) hasEntry(list []*yamlv3.Node, searchEntry *yamlv3.Node) bool {
-	var searchEntryHash = compare.calcNodeHash(searchEntry)
+func (compare *compare) hasEntry(path ytbx.Path, list []*yamlv3.Node, searchEntry *yamlv3.Node) bool {
+	var searchEntryHash = compare.calcNodeHash(anyListEntry(path), searchEntry)
 	for _, listEntry := range list {
-		if searchEntryHash == compare.calcNodeHash(listEntry) {
+		if searchEntryHash == compare.calcNodeHash(anyListEntry(path), listEntry) {
 			return true
 		}
 	}
@@ -822,6 +1489,36 @@ func findOrderChangesInNamedEntryLists(fromNames, toNames []string) []Detail {
 	return orderchanges
 }
 
+// findOrderChangesInMappings returns an order change in case the keys that
+// exist in both maps are not in the same order
+func findOrderChangesInMappings(from, to *yamlv3.Node) []Detail {
+	fromKeys, toKeys := commonKeys(from, to), commonKeys(to, from)
+	for idx := range fromKeys {
+		if fromKeys[idx] != toKeys[idx] {
+			return []Detail{{
+				Kind: ORDERCHANGE,
+				From: AsSequenceNode(fromKeys...),
+				To:   AsSequenceNode(toKeys...),
+			}}
+		}
+	}
+
+	return []Detail{}
+}
+
+// commonKeys returns the keys of the first map that also exist in the second
+// map, in the order of the first map
+func commonKeys(mapping, other *yamlv3.Node) []string {
+	var result []string
+	for i := 0; i < len(mapping.Content); i += 2 {
+		if _, ok := findValueByKey(other, mapping.Content[i].Value); ok {
+			result = append(result, mapping.Content[i].Value)
+		}
+	}
+
+	return result
+}
+
 func packChangesAndAddToResult(list []Diff, path ytbx.Path, orderchanges []Detail, additions, removals []*yamlv3.Node) ([]Diff, error) {
 	// Prepare a diff for this path to added to the result set (if there are changes)
 	diff := Diff{Path: &path, Details: []Detail{}}
@@ -882,6 +1579,18 @@ func findValueByKey(mappingNode *yamlv3.Node, key string) (*yamlv3.Node, bool) {
 	return nil, false
 }
 
+// findEntry returns the key node and the value node (without following an
+// alias) of the map entry with the given key
+func findEntry(mappingNode *yamlv3.Node, key string) (*yamlv3.Node, *yamlv3.Node, bool) {
+	for i := 0; i < len(mappingNode.Content); i += 2 {
+		if k := followAlias(mappingNode.Content[i]); k.Value == key {
+			return k, mappingNode.Content[i+1], true
+		}
+	}
+
+	return nil, nil, false
+}
+
 func (compare *compare) listItemIdentifierCandidates() []string {
 	// Set default candidates that are most widly used
 	var candidates = []string{"name", "key", "id"}
@@ -991,6 +1700,66 @@ func (compare *compare) getNonStandardIdentifierFromNamedLists(listA, listB *yam
 	return nil
 }
 
+// getIdentifierFromRules returns the identifier of the first identifier rule
+// that matches the path and that can be used for all entries of both lists
+func (compare *compare) getIdentifierFromRules(path ytbx.Path, listA, listB *yamlv3.Node) (listItemIdentifier, error) {
+	for _, rule := range compare.settings.IdentifierRules {
+		if !newPathPattern(rule.PathPattern).matches(path) {
+			continue
+		}
+
+		if identifier, err := compare.getIdentifierFromFields(rule.Fields, listA, listB); err == nil {
+			return identifier, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no identifier rule applies to the list at %s", path.ToGoPatchStyle())
+}
+
+// getIdentifierFromFields returns an identifier based on the given fields, if
+// all entries of both lists have unique scalar values for these fields
+func (compare *compare) getIdentifierFromFields(fields []string, listA, listB *yamlv3.Node) (listItemIdentifier, error) {
+	var identifier listItemIdentifier = &compositeField{fields}
+	if len(fields) == 1 {
+		identifier = &singleField{fields[0]}
+	}
+
+	isUnique := func(sequenceNode *yamlv3.Node) bool {
+		names := map[string]struct{}{}
+		for _, entry := range sequenceNode.Content {
+			if followAlias(entry).Kind != yamlv3.MappingNode {
+				return false
+			}
+
+			// only scalar values are suitable to identify an entry
+			for _, field := range fields {
+				if value, err := grab(followAlias(entry), field); err != nil || followAlias(value).Kind != yamlv3.ScalarNode {
+					return false
+				}
+			}
+
+			name, err := identifier.Name(followAlias(entry))
+			if err != nil {
+				return false
+			}
+
+			if _, found := names[name]; found {
+				return false
+			}
+
+			names[name] = struct{}{}
+		}
+
+		return true
+	}
+
+	if len(fields) == 0 || !isUnique(listA) || !isUnique(listB) {
+		return nil, fmt.Errorf("unable to use %s as an unique identifier", strings.Join(fields, ", "))
+	}
+
+	return identifier, nil
+}
+
 // getIdentifierFromKubernetesEntityList returns 'metadata.name' as a field identifier if the provided objects all have the key.
 func (compare *compare) getIdentifierFromKubernetesEntityList(listA, listB *yamlv3.Node) (listItemIdentifier, error) {
 	if !compare.settings.KubernetesEntityDetection {
@@ -1034,80 +1803,54 @@ func isEmptyDocument(node *yamlv3.Node) bool {
 	return false
 }
 
-func (compare *compare) createLookUpMap(sequenceNode *yamlv3.Node) map[uint64][]int {
-	result := make(map[uint64][]int, len(sequenceNode.Content))
-	for idx, entry := range sequenceNode.Content {
-		hash := compare.calcNodeHash(entry)
-		if _, ok := result[hash]; !ok {
-			result[hash] = []int{}
-		}
-
-		result[hash] = append(result[hash], idx)
+// isAbsent returns whether the given node is considered to be equivalent to an
+// absent value, which depends on whether null values or empty values (maps,
+// lists, strings) are configured to be treated like absent values
+func (compare *compare) isAbsent(node *yamlv3.Node) bool {
+	if node.Kind == yamlv3.DocumentNode && len(node.Content) == 1 {
+		node = node.Content[0]
 	}
 
-	return result
-}
-
-func (compare *compare) basicType(node *yamlv3.Node) interface{} {
-	switch node.Kind {
-	case yamlv3.DocumentNode:
-		panic("document nodes are not supported to be translated into a basic type")
-
-	case yamlv3.MappingNode:
-		result := map[interface{}]interface{}{}
-		for i := 0; i < len(node.Content); i += 2 {
-			k, v := followAlias(node.Content[i]), followAlias(node.Content[i+1])
-			result[compare.basicType(k)] = compare.basicType(v)
-		}
-
-		return result
-
-	case yamlv3.SequenceNode:
-		result := []interface{}{}
-
-		if compare.settings.IgnoreOrderChanges {
-			sortNode(node)
-		}
-
-		for _, entry := range node.Content {
-			result = append(result, compare.basicType(followAlias(entry)))
-		}
-
-		return result
-
+	switch node = followAlias(node); node.Kind {
 	case yamlv3.ScalarNode:
-		return compare.formatScalar(node)
+		switch {
+		case node.Tag == "!!null":
+			return compare.settings.NullAsAbsent
 
-	case yamlv3.AliasNode:
-		return compare.basicType(node.Alias)
+		case node.Tag == "!!str" && node.Value == "":
+			return compare.settings.EmptyAsAbsent
+		}
 
-	default:
-		panic("should be unreachable")
+	case yamlv3.MappingNode, yamlv3.SequenceNode:
+		return compare.settings.EmptyAsAbsent && len(node.Content) == 0
 	}
-}
 
-func (compare *compare) calcNodeHash(node *yamlv3.Node) (hash uint64) {
-	var err error
-
-	switch node.Kind {
-	case yamlv3.MappingNode, yamlv3.SequenceNode:
-		hash, err = hashstructure.Hash(compare.basicType(node), nil)
+	return false
+}
 
-	case yamlv3.ScalarNode:
-		hash, err = hashstructure.Hash(compare.formatScalar(node), nil)
+func (compare *compare) createLookUpMap(path ytbx.Path, sequenceNode *yamlv3.Node) (map[uint64][]int, error) {
+	hashes, err := compare.listEntryHashes(path, sequenceNode)
+	if err != nil {
+		return nil, err
+	}
 
-	case yamlv3.AliasNode:
-		hash = compare.calcNodeHash(followAlias(node))
+	result := make(map[uint64][]int, len(sequenceNode.Content))
+	for idx, hash := range hashes {
+		if _, ok := result[hash]; !ok {
+			result[hash] = []int{}
+		}
 
-	default:
-		err = fmt.Errorf("kind %v is not supported", node.Kind)
+		result[hash] = append(result[hash], idx)
 	}
 
-	if err != nil {
-		panic(fmt.Errorf("failed to calculate hash of %#v: %w", node.Value, err))
-	}
+	return result, nil
+}
 
-	return hash
+// anyListEntry returns the path of an entry of the list at the given path
+// regardless of its position, since the hash of a list entry must not depend on
+// its position in the list
+func anyListEntry(path ytbx.Path) ytbx.Path {
+	return ytbx.NewPathWithIndexedListElement(path, -1)
 }
 
 func (compare *compare) formatScalar(node *yamlv3.Node) string {
@@ -1120,27 +1863,6 @@ func (compare *compare) formatScalar(node *yamlv3.Node) string {
 	return value
 }
 
-func sortNode(node *yamlv3.Node) {
-	sort.Slice(node.Content, func(i, j int) bool {
-		a, b := node.Content[i], node.Content[j]
-
-		if a.Kind != b.Kind {
-			return a.Kind < b.Kind
-		}
-
-		if a.Tag != b.Tag {
-			return strings.Compare(a.Tag, b.Tag) < 0
-		}
-
-		switch a.Kind {
-		case yamlv3.ScalarNode:
-			return strings.Compare(a.Value, b.Value) < 0
-		}
-
-		return len(a.Content) < len(b.Content)
-	})
-}
-
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -1210,7 +1932,7 @@ func ChangeRoot(inputFile *ytbx.InputFile, path string, useGoPatchPaths bool, tr
 
 	// Parse path string and create nicely formatted output path
 	if resolvedPath, err := ytbx.ParsePathString(path, originalRoot); err == nil {
-		path = pathToString(&resolvedPath, useGoPatchPaths, multipleDocuments)
+		path = pathToString(&resolvedPath, nil, useGoPatchPaths, multipleDocuments)
 	}
 
 	inputFile.Note = fmt.Sprintf("YAML root was changed to %s", path)
@@ -1218,14 +1940,14 @@ func ChangeRoot(inputFile *ytbx.InputFile, path string, useGoPatchPaths bool, tr
 	return nil
 }
 
-func pathToString(path *ytbx.Path, useGoPatchPaths bool, showPathRoot bool) string {
+func pathToString(path *ytbx.Path, embedding *ytbx.Path, useGoPatchPaths bool, showPathRoot bool) string {
 	var result string
 
 	if useGoPatchPaths {
-		result = styledGoPatchPath(path)
+		result = styledGoPatchPath(path, embedding)
 
 	} else {
-		result = styledDotStylePath(path)
+		result = styledDotStylePath(path, embedding)
 	}
 
 	if path != nil && showPathRoot {
@@ -0,0 +1,172 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/homeport/dyff/pkg/dyff"
+)
+
+// generateInputFile creates a document with a list of named entries, where
+// every entry consists of a nested map and a list, the modified entries differ
+// in one value deep inside of the entry
+func generateInputFile(b *testing.B, entries int, modified int) ytbx.InputFile {
+	var buf strings.Builder
+	buf.WriteString("list:\n")
+	for i := 0; i < entries; i++ {
+		version := "v1"
+		if i < modified {
+			version = "v2"
+		}
+
+		fmt.Fprintf(&buf, "- name: entry-%d\n", i)
+		fmt.Fprintf(&buf, "  spec:\n    image: registry/image-%d:%s\n    replicas: %d\n", i, version, i%5)
+		fmt.Fprintf(&buf, "    ports:\n    - %d\n    - %d\n", 8000+i, 9000+i)
+	}
+
+	var node yamlv3.Node
+	if err := yamlv3.Unmarshal([]byte(buf.String()), &node); err != nil {
+		b.Fatal(err)
+	}
+
+	return ytbx.InputFile{Documents: []*yamlv3.Node{&node}}
+}
+
+// reverseList reverses the order of the entries of the list in the document
+func reverseList(inputFile ytbx.InputFile) ytbx.InputFile {
+	list := inputFile.Documents[0].Content[0].Content[1]
+	for i, j := 0, len(list.Content)-1; i < j; i, j = i+1, j-1 {
+		list.Content[i], list.Content[j] = list.Content[j], list.Content[i]
+	}
+
+	return inputFile
+}
+
+func benchmarkCompare(b *testing.B, from, to ytbx.InputFile, compareOptions ...dyff.CompareOption) {
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := dyff.CompareInputFiles(from, to, compareOptions...); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCompareNamedEntryLists(b *testing.B) {
+	for _, size := range []int{100, 1000, 5000} {
+		b.Run(fmt.Sprintf("entries=%d", size), func(b *testing.B) {
+			benchmarkCompare(b,
+				generateInputFile(b, size, 0),
+				reverseList(generateInputFile(b, size, size/10)),
+			)
+		})
+	}
+}
+
+func BenchmarkCompareListsIgnoringOrder(b *testing.B) {
+	for _, size := range []int{100, 1000, 5000} {
+		b.Run(fmt.Sprintf("entries=%d", size), func(b *testing.B) {
+			benchmarkCompare(b,
+				generateInputFile(b, size, 0),
+				reverseList(generateInputFile(b, size, size/10)),
+				dyff.IgnoreOrderChanges(true),
+			)
+		})
+	}
+}
+
+func BenchmarkCompareMoves(b *testing.B) {
+	for _, size := range []int{100, 1000} {
+		b.Run(fmt.Sprintf("entries=%d", size), func(b *testing.B) {
+			benchmarkCompare(b,
+				generateInputFile(b, size, 0),
+				generateInputFile(b, size, size/10),
+				dyff.DetectMapEntryMoves(true),
+			)
+		})
+	}
+}
+
+func BenchmarkCompareDocumentsConcurrently(b *testing.B) {
+	generateDocuments := func(modified int) ytbx.InputFile {
+		var inputFile ytbx.InputFile
+		for i := 0; i < 500; i++ {
+			document := generateInputFile(b, 20, modified)
+			document.Documents[0].Content[0].Content = append(document.Documents[0].Content[0].Content,
+				&yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: "name"},
+				&yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: fmt.Sprintf("document-%d", i)},
+			)
+
+			inputFile.Documents = append(inputFile.Documents, document.Documents...)
+		}
+
+		return inputFile
+	}
+
+	from, to := generateDocuments(0), generateDocuments(2)
+	for _, concurrency := range []int{1, 4, 0} {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			benchmarkCompare(b, from, to, dyff.Concurrency(concurrency))
+		})
+	}
+}
+
+// BenchmarkCompareLongSimpleLists compares long lists without identifiers,
+// which are aligned using their longest common subsequence
+func BenchmarkCompareLongSimpleLists(b *testing.B) {
+	generateSimpleList := func(entries int, offset int) ytbx.InputFile {
+		list := &yamlv3.Node{Kind: yamlv3.SequenceNode, Tag: "!!seq"}
+		for i := 0; i < entries; i++ {
+			value := fmt.Sprintf("entry-%d", i)
+			if i%100 == 0 {
+				value = fmt.Sprintf("changed-entry-%d", i+offset)
+			}
+
+			list.Content = append(list.Content, &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: value})
+		}
+
+		return ytbx.InputFile{Documents: []*yamlv3.Node{{
+			Kind: yamlv3.DocumentNode,
+			Content: []*yamlv3.Node{{
+				Kind:    yamlv3.MappingNode,
+				Tag:     "!!map",
+				Content: []*yamlv3.Node{{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: "list"}, list},
+			}},
+		}}}
+	}
+
+	for _, size := range []int{1000, 10000} {
+		b.Run(fmt.Sprintf("entries=%d", size), func(b *testing.B) {
+			benchmarkCompare(b,
+				generateSimpleList(size, 0),
+				generateSimpleList(size, 1),
+				dyff.PositionalLists(true),
+			)
+		})
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// comments returns the changes of the head, line, and foot comments of the two
+// nodes, where a comment that only exists on one side is an added or removed
+// comment, which is expressed by a nil node on the other side
+func comments(from, to *yamlv3.Node) []Detail {
+	var result []Detail
+	for _, comment := range []struct {
+		note     string
+		from, to string
+	}{
+		{"head comment", from.HeadComment, to.HeadComment},
+		{"line comment", from.LineComment, to.LineComment},
+		{"foot comment", from.FootComment, to.FootComment},
+	} {
+		if comment.from == comment.to {
+			continue
+		}
+
+		result = append(result, Detail{
+			Kind: COMMENT,
+			From: commentNode(comment.from),
+			To:   commentNode(comment.to),
+			Note: comment.note,
+		})
+	}
+
+	return result
+}
+
+func commentNode(comment string) *yamlv3.Node {
+	if comment == "" {
+		return nil
+	}
+
+	return &yamlv3.Node{
+		Kind:  yamlv3.ScalarNode,
+		Tag:   "!!str",
+		Value: comment,
+	}
+}
+
+// withKeyComments returns a copy of the map value that also has the comments
+// of its key, since a comment of a map entry can be located at either of both
+func withKeyComments(key, value *yamlv3.Node) *yamlv3.Node {
+	join := func(a, b string) string {
+		switch {
+		case a == "":
+			return b
+
+		case b == "":
+			return a
+		}
+
+		return a + "\n" + b
+	}
+
+	result := *value
+	result.HeadComment = join(key.HeadComment, value.HeadComment)
+	result.LineComment = join(key.LineComment, value.LineComment)
+	result.FootComment = join(key.FootComment, value.FootComment)
+
+	return &result
+}
+
+// unchangedEntryChanges compares two entries with the same content and only
+// returns the changes of their comments, anchors, and aliases, including the
+// ones of nested nodes
+func (compare *compare) unchangedEntryChanges(path ytbx.Path, from, to *yamlv3.Node) ([]Diff, error) {
+	diffs, err := compare.objects(path, followAlias(from), followAlias(to))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Diff, 0, len(diffs))
+	for _, diff := range diffs {
+		var details []Detail
+		for _, detail := range diff.Details {
+			if detail.Kind == COMMENT || detail.Kind == ANCHOR {
+				details = append(details, detail)
+			}
+		}
+
+		if len(details) > 0 {
+			result = append(result, Diff{Path: diff.Path, Details: details})
+		}
+	}
+
+	return compare.withAnchorChanges(path, from, to, result), nil
+}
+
+// withComments adds the comment changes of the nodes to the differences found
+// for the same path, or reports them as a separate difference at the path
+func withComments(path ytbx.Path, from, to *yamlv3.Node, diffs []Diff) []Diff {
+	return withDetails(path, comments(from, to), diffs)
+}
+
+// withDetails adds the details to the differences found for the same path, or
+// reports them as a separate difference at the path
+func withDetails(path ytbx.Path, details []Detail, diffs []Diff) []Diff {
+	if len(details) == 0 {
+		return diffs
+	}
+
+	if len(diffs) > 0 && diffs[0].Path != nil && diffs[0].Path.ToGoPatchStyle() == path.ToGoPatchStyle() {
+		diffs[0].Details = append(details, diffs[0].Details...)
+		return diffs
+	}
+
+	return append([]Diff{{Path: &path, Details: details}}, diffs...)
+}
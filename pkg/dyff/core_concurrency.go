@@ -0,0 +1,112 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// documentPair is a pair of matching documents from both input files, which
+// can be compared independently of all other documents
+type documentPair struct {
+	path ytbx.Path
+	from *yamlv3.Node
+	to   *yamlv3.Node
+}
+
+// workers returns the number of documents that are compared in parallel
+func (compare *compare) workers() int {
+	if compare.settings.Concurrency < 1 {
+		return runtime.NumCPU()
+	}
+
+	return compare.settings.Concurrency
+}
+
+// documentPair compares the documents of the pair, panics are converted into
+// errors, since they cannot be recovered outside of the worker goroutine
+func (compare *compare) documentPair(pair documentPair) (result []Diff, err error) {
+	defer recoverPanic(&err)
+	return compare.objects(pair.path, pair.from, pair.to)
+}
+
+// documentPairs compares the document pairs using a pool of workers. The
+// differences are returned in the order of the pairs, in case of errors, the
+// error of the first pair that failed is returned.
+func (compare *compare) documentPairs(pairs []documentPair) ([]Diff, error) {
+	workers := min(compare.workers(), len(pairs))
+	if workers <= 1 {
+		var result []Diff
+		for _, pair := range pairs {
+			diffs, err := compare.documentPair(pair)
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, diffs...)
+		}
+
+		return result, nil
+	}
+
+	results := make([][]Diff, len(pairs))
+	errs := make([]error, len(pairs))
+
+	var failed atomic.Bool
+	var next atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for !failed.Load() {
+				idx := int(next.Add(1) - 1)
+				if idx >= len(pairs) {
+					return
+				}
+
+				results[idx], errs[idx] = compare.documentPair(pairs[idx])
+				if errs[idx] != nil {
+					failed.Store(true)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	var result []Diff
+	for idx := range pairs {
+		if errs[idx] != nil {
+			return nil, errs[idx]
+		}
+
+		result = append(result, results[idx]...)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,362 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// GoPatchOperation is an operation of a go-patch operations file
+type GoPatchOperation struct {
+	Type  string       `yaml:"type"`
+	Path  string       `yaml:"path"`
+	Value *yamlv3.Node `yaml:"value,omitempty"`
+}
+
+// UnmarshalYAML reads the operation from a YAML map, the value is kept as a
+// node
+func (operation *GoPatchOperation) UnmarshalYAML(node *yamlv3.Node) error {
+	if node.Kind != yamlv3.MappingNode {
+		return fmt.Errorf("failed to parse go-patch operation, expected a map")
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		switch key {
+		case "type":
+			operation.Type = value.Value
+
+		case "path":
+			operation.Path = value.Value
+
+		case "value":
+			operation.Value = value
+		}
+	}
+
+	return nil
+}
+
+// ApplyGoPatch applies the go-patch operations file (for example a BOSH ops
+// file) to a copy of the document and returns the patched copy, the document
+// itself stays untouched. Key order, comments, and anchors of the document are
+// preserved.
+func ApplyGoPatch(document *yamlv3.Node, patch []byte) (*yamlv3.Node, error) {
+	var operations []GoPatchOperation
+	if err := yamlv3.Unmarshal(patch, &operations); err != nil {
+		return nil, fmt.Errorf("failed to parse go-patch operations: %w", err)
+	}
+
+	root := cloneNode(document, map[*yamlv3.Node]*yamlv3.Node{})
+	content := &root
+	if root.Kind == yamlv3.DocumentNode && len(root.Content) == 1 {
+		content = &root.Content[0]
+	}
+
+	for _, operation := range operations {
+		if err := applyGoPatchOperation(content, operation); err != nil {
+			return nil, err
+		}
+	}
+
+	repairAliases(root)
+	return root, nil
+}
+
+// goPatchToken is a segment of a go-patch path, which is either a map key, a
+// list index, a list entry with a matching key and value (`name=foo`), or the
+// end of a list (`-`)
+type goPatchToken struct {
+	key      string
+	value    string
+	idx      int
+	kind     goPatchTokenKind
+	modifier string
+	optional bool
+}
+
+type goPatchTokenKind int
+
+const (
+	goPatchKey goPatchTokenKind = iota
+	goPatchIndex
+	goPatchMatch
+	goPatchEnd
+)
+
+func (token goPatchToken) String() string {
+	switch token.kind {
+	case goPatchIndex:
+		return strconv.Itoa(token.idx)
+
+	case goPatchMatch:
+		return token.key + "=" + token.value
+
+	case goPatchEnd:
+		return "-"
+	}
+
+	return token.key
+}
+
+// parseGoPatchPath parses a go-patch path, all segments after the first
+// optional segment (`?` suffix) are optional, too
+func parseGoPatchPath(path string) ([]goPatchToken, error) {
+	if !strings.HasPrefix(path, "/") {
+		return nil, fmt.Errorf("invalid go-patch path %q, it has to start with a slash", path)
+	}
+
+	if path == "/" {
+		return nil, nil
+	}
+
+	var result []goPatchToken
+	var optional bool
+	for _, segment := range strings.Split(path[1:], "/") {
+		if strings.HasSuffix(segment, "?") {
+			segment, optional = strings.TrimSuffix(segment, "?"), true
+		}
+
+		segment = strings.NewReplacer("~1", "/", "~0", "~").Replace(segment)
+		token := goPatchToken{key: segment, optional: optional}
+
+		base := segment
+		for _, modifier := range []string{"prev", "next", "before", "after"} {
+			if trimmed, ok := strings.CutSuffix(segment, ":"+modifier); ok {
+				base, token.modifier = trimmed, modifier
+				break
+			}
+		}
+
+		idx, err := strconv.Atoi(base)
+		switch {
+		case segment == "-":
+			token.kind = goPatchEnd
+
+		case err == nil:
+			token.kind, token.idx = goPatchIndex, idx
+
+		case strings.Contains(base, "="):
+			token.kind = goPatchMatch
+			token.key, token.value, _ = strings.Cut(base, "=")
+
+		default:
+			token.modifier = ""
+		}
+
+		result = append(result, token)
+	}
+
+	return result, nil
+}
+
+func applyGoPatchOperation(root **yamlv3.Node, operation GoPatchOperation) error {
+	tokens, err := parseGoPatchPath(operation.Path)
+	if err != nil {
+		return err
+	}
+
+	switch operation.Type {
+	case "replace":
+		if operation.Value == nil {
+			return fmt.Errorf("failed to apply replace operation at %q: no value", operation.Path)
+		}
+
+		if len(tokens) == 0 {
+			*root = copyNode(operation.Value, nil)
+			return nil
+		}
+
+	case "remove":
+		if len(tokens) == 0 {
+			return fmt.Errorf("failed to apply remove operation, the root cannot be removed")
+		}
+
+	default:
+		return fmt.Errorf("unsupported go-patch operation type %q", operation.Type)
+	}
+
+	node := *root
+	for i, token := range tokens {
+		last := i == len(tokens)-1
+		if node.Kind == yamlv3.AliasNode {
+			*node = *copyNode(node, nil)
+		}
+
+		var slot **yamlv3.Node
+		switch token.kind {
+		case goPatchKey:
+			if node.Kind != yamlv3.MappingNode {
+				return fmt.Errorf("failed to find %q in %q, expected a map", token, operation.Path)
+			}
+
+			idx := mappingKeyIndex(node, token.key)
+			switch {
+			case idx >= 0 && last && operation.Type == "remove":
+				node.Content = append(node.Content[:idx], node.Content[idx+2:]...)
+				return nil
+
+			case idx >= 0:
+				slot = &node.Content[idx+1]
+
+			case !token.optional:
+				return fmt.Errorf("failed to find %q in %q", token, operation.Path)
+
+			case operation.Type == "remove":
+				return nil
+
+			default:
+				node.Content = append(node.Content, &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: token.key}, newGoPatchContainer(tokens[i+1:]))
+				slot = &node.Content[len(node.Content)-1]
+			}
+
+		case goPatchEnd:
+			if node.Kind != yamlv3.SequenceNode || !last || operation.Type != "replace" {
+				return fmt.Errorf("failed to apply %q, the end of a list can only be used to append entries", operation.Path)
+			}
+
+			node.Content = append(node.Content, copyNode(operation.Value, nil))
+			return nil
+
+		case goPatchIndex, goPatchMatch:
+			if node.Kind != yamlv3.SequenceNode {
+				return fmt.Errorf("failed to find %q in %q, expected a list", token, operation.Path)
+			}
+
+			idx, err := goPatchListIndex(node, token)
+			if err != nil {
+				return fmt.Errorf("failed to apply %q: %w", operation.Path, err)
+			}
+
+			switch {
+			case idx < 0 && (!token.optional || token.kind == goPatchIndex):
+				return fmt.Errorf("failed to find %q in %q", token, operation.Path)
+
+			case idx < 0 && operation.Type == "remove":
+				return nil
+
+			case idx < 0 && last:
+				node.Content = append(node.Content, copyNode(operation.Value, nil))
+				return nil
+
+			case idx < 0:
+				node.Content = append(node.Content, &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map", Content: []*yamlv3.Node{
+					{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: token.key},
+					{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: token.value},
+				}})
+				idx = len(node.Content) - 1
+			}
+
+			if last && (token.modifier == "before" || token.modifier == "after") {
+				if operation.Type != "replace" {
+					return fmt.Errorf("failed to apply %q, only entries can be inserted before or after other entries", operation.Path)
+				}
+
+				if token.modifier == "after" {
+					idx++
+				}
+
+				node.Content = append(node.Content, nil)
+				copy(node.Content[idx+1:], node.Content[idx:])
+				node.Content[idx] = copyNode(operation.Value, nil)
+				return nil
+			}
+
+			if last && operation.Type == "remove" {
+				node.Content = append(node.Content[:idx], node.Content[idx+1:]...)
+				return nil
+			}
+
+			slot = &node.Content[idx]
+		}
+
+		if last {
+			value := copyNode(operation.Value, nil)
+			keepComments(*slot, value)
+			*slot = value
+			return nil
+		}
+
+		node = *slot
+	}
+
+	return nil
+}
+
+// goPatchListIndex returns the index of the list entry the token refers to,
+// or -1 if there is no matching entry
+func goPatchListIndex(list *yamlv3.Node, token goPatchToken) (int, error) {
+	idx := -1
+	switch token.kind {
+	case goPatchIndex:
+		idx = token.idx
+		if idx < 0 {
+			idx += len(list.Content)
+		}
+
+	case goPatchMatch:
+		for i, entry := range list.Content {
+			if value, ok := findValueByKey(followAlias(entry), token.key); ok && followAlias(value).Value == token.value {
+				if idx >= 0 {
+					return 0, fmt.Errorf("multiple list entries match %q", token)
+				}
+
+				idx = i
+			}
+		}
+
+		if idx < 0 {
+			return -1, nil
+		}
+	}
+
+	switch token.modifier {
+	case "prev":
+		idx--
+
+	case "next":
+		idx++
+	}
+
+	if idx < 0 || idx >= len(list.Content) {
+		if token.kind == goPatchIndex || token.modifier != "" {
+			return 0, fmt.Errorf("list index %d of %q is out of range", idx, token)
+		}
+
+		return -1, nil
+	}
+
+	return idx, nil
+}
+
+// newGoPatchContainer creates the map or list that is needed to continue with
+// the remaining path segments
+func newGoPatchContainer(remaining []goPatchToken) *yamlv3.Node {
+	if len(remaining) > 0 && remaining[0].kind != goPatchKey {
+		return &yamlv3.Node{Kind: yamlv3.SequenceNode, Tag: "!!seq"}
+	}
+
+	return &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+}
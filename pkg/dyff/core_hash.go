@@ -0,0 +1,244 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// FNV-1a (64 bit) parameters, the hash is calculated inline to not allocate a
+// hash function for every node
+const (
+	fnvOffset64 uint64 = 14695981039346656037
+	fnvPrime64  uint64 = 1099511628211
+)
+
+// hash prefixes to make sure that different kinds of nodes with the same
+// content do not end up with the same hash
+const (
+	hashScalar uint64 = iota + 1
+	hashMapping
+	hashMappingEntry
+	hashSequence
+	hashDocument
+)
+
+// nodeHashes memoizes the hashes of nodes, since the same nodes are hashed over
+// and over again while lists are compared, for example when entries are looked
+// up, when order changes are detected, or when moves are detected
+type nodeHashes struct {
+	sync.RWMutex
+	cache map[nodeHashKey]uint64
+}
+
+// nodeHashKey identifies a node hash, the path is only part of the key if the
+// hash depends on it (see pathDependentHashes)
+type nodeHashKey struct {
+	node *yamlv3.Node
+	path string
+}
+
+func newNodeHashes() *nodeHashes {
+	return &nodeHashes{cache: map[nodeHashKey]uint64{}}
+}
+
+func (hashes *nodeHashes) get(key nodeHashKey) (uint64, bool) {
+	if hashes == nil {
+		return 0, false
+	}
+
+	hashes.RLock()
+	defer hashes.RUnlock()
+
+	hash, ok := hashes.cache[key]
+	return hash, ok
+}
+
+func (hashes *nodeHashes) put(key nodeHashKey, hash uint64) {
+	if hashes == nil {
+		return
+	}
+
+	hashes.Lock()
+	defer hashes.Unlock()
+
+	hashes.cache[key] = hash
+}
+
+// pathDependentHashes returns whether the hash of a node depends on its path,
+// which is the case if list order rules or schemas declare which lists are
+// sets, or if scalars are compared as quantities, which depends on their path
+func (compare *compare) pathDependentHashes() bool {
+	return len(compare.settings.ListOrderRules) > 0 || compare.settings.Schemas != nil || compare.settings.KubernetesQuantities
+}
+
+// calcNodeHash calculates the hash of the node located at the given path, the
+// path is used to find out which lists inside the node are sets. The hash is
+// structural: the order of map entries and the order of entries of lists that
+// are sets do not matter. Hashes are memoized per node.
+func (compare *compare) calcNodeHash(path ytbx.Path, node *yamlv3.Node) uint64 {
+	key := nodeHashKey{node: node}
+	if compare.pathDependentHashes() {
+		key.path = path.String()
+	}
+
+	if hash, ok := compare.hashes.get(key); ok {
+		return hash
+	}
+
+	hash := compare.nodeHash(path, node)
+	compare.hashes.put(key, hash)
+	return hash
+}
+
+// listEntryHashes calculates the hashes of all entries of the list, it stops
+// once the context is done, since hashing large lists can take a while
+func (compare *compare) listEntryHashes(path ytbx.Path, list *yamlv3.Node) ([]uint64, error) {
+	result := make([]uint64, len(list.Content))
+	for i, entry := range list.Content {
+		if err := compare.ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		result[i] = compare.calcNodeHash(anyListEntry(path), entry)
+	}
+
+	return result, nil
+}
+
+func (compare *compare) nodeHash(path ytbx.Path, node *yamlv3.Node) uint64 {
+	// child paths are only required if the hash depends on the path
+	childPath := func(create func() ytbx.Path) ytbx.Path {
+		if compare.pathDependentHashes() {
+			return create()
+		}
+
+		return path
+	}
+
+	switch node = compare.normalizeTag(node); node.Kind {
+	case yamlv3.DocumentNode:
+		hash := fnvUint64(fnvOffset64, hashDocument)
+		for _, entry := range node.Content {
+			hash = fnvUint64(hash, compare.calcNodeHash(path, entry))
+		}
+
+		return hash
+
+	case yamlv3.MappingNode:
+		if compare.settings.ResolveMergeKeys {
+			node = resolveMergeKeys(node)
+		}
+
+		// the entry hashes are summed up, so that the order does not matter
+		var sum uint64
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := followAlias(node.Content[i])
+			valuePath := childPath(func() ytbx.Path { return ytbx.NewPathWithNamedElement(path, key.Value) })
+
+			// keys are looked up by their notation, not by their value
+			var keyHash uint64
+			if key.Kind == yamlv3.ScalarNode {
+				keyHash = fnvString(fnvUint64(fnvOffset64, hashScalar), compare.formatScalar(key))
+			} else {
+				keyHash = compare.calcNodeHash(path, key)
+			}
+
+			entry := fnvUint64(fnvOffset64, hashMappingEntry)
+			entry = fnvUint64(entry, keyHash)
+			entry = fnvUint64(entry, compare.calcNodeHash(valuePath, node.Content[i+1]))
+			sum += entry
+		}
+
+		return fnvUint64(fnvUint64(fnvOffset64, hashMapping), sum)
+
+	case yamlv3.SequenceNode:
+		entryPath := childPath(func() ytbx.Path { return anyListEntry(path) })
+
+		hashes := make([]uint64, len(node.Content))
+		for i, entry := range node.Content {
+			hashes[i] = compare.calcNodeHash(entryPath, entry)
+		}
+
+		if compare.ignoreOrderChangesAt(path) {
+			sort.Slice(hashes, func(i, j int) bool { return hashes[i] < hashes[j] })
+		}
+
+		hash := fnvUint64(fnvOffset64, hashSequence)
+		for _, entry := range hashes {
+			hash = fnvUint64(hash, entry)
+		}
+
+		return hash
+
+	case yamlv3.ScalarNode:
+		return fnvString(fnvUint64(fnvOffset64, hashScalar), compare.scalarHashValue(path, node))
+
+	case yamlv3.AliasNode:
+		return compare.calcNodeHash(path, followAlias(node))
+
+	default:
+		panic("should be unreachable")
+	}
+}
+
+// scalarHashValue returns the value of the scalar to be hashed, which is the
+// normalized value in case scalars are compared by their value, so that
+// equivalent scalars (e.g. `1.0` and `1`, or `1Gi` and `1024Mi`) have the same
+// hash, just like they are considered to be equal when they are compared
+func (compare *compare) scalarHashValue(path ytbx.Path, node *yamlv3.Node) string {
+	if compare.settings.KubernetesQuantities && isQuantityPath(path) && isKubernetesResource(path) {
+		if value, _, ok := parseQuantity(node.Value); ok {
+			return value.RatString()
+		}
+	}
+
+	if compare.settings.SemanticScalars {
+		if value, ok := normalizedScalar(node); ok {
+			return value
+		}
+	}
+
+	return compare.formatScalar(node)
+}
+
+func fnvUint64(hash uint64, value uint64) uint64 {
+	for i := 0; i < 8; i++ {
+		hash ^= value & 0xff
+		hash *= fnvPrime64
+		value >>= 8
+	}
+
+	return hash
+}
+
+func fnvString(hash uint64, value string) uint64 {
+	for i := 0; i < len(value); i++ {
+		hash ^= uint64(value[i])
+		hash *= fnvPrime64
+	}
+
+	return hash
+}
@@ -25,6 +25,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/gonvenience/ytbx"
 	yamlv3 "gopkg.in/yaml.v3"
 )
 
@@ -34,10 +35,6 @@ type listItemIdentifier interface {
 	// is not possible due to missing fields
 	Name(mappingNode *yamlv3.Node) (string, error)
 
-	// FindNodeByName returns the node that matches the given name, or an error in
-	// case it cannot find such an entry or required fields are missing
-	FindNodeByName(sequenceNode *yamlv3.Node, name string) (*yamlv3.Node, error)
-
 	// String returns a reprensation or explanation of the identifier itself
 	String() string
 }
@@ -52,21 +49,6 @@ type singleField struct {
 
 var _ listItemIdentifier = &singleField{}
 
-func (sf *singleField) FindNodeByName(sequenceNode *yamlv3.Node, name string) (*yamlv3.Node, error) {
-	for _, mappingNode := range sequenceNode.Content {
-		nameOfNode, err := sf.Name(mappingNode)
-		if err != nil {
-			return nil, err
-		}
-
-		if nameOfNode == name {
-			return mappingNode, nil
-		}
-	}
-
-	return nil, fmt.Errorf("failed to find mapping entry with name %q", name)
-}
-
 func (sf *singleField) Name(mappingNode *yamlv3.Node) (string, error) {
 	result, err := grab(mappingNode, sf.IdentifierFieldName)
 	if err != nil {
@@ -88,21 +70,6 @@ type k8sItemIdentifier struct{}
 
 var k8sItem listItemIdentifier = &k8sItemIdentifier{}
 
-func (i *k8sItemIdentifier) FindNodeByName(sequenceNode *yamlv3.Node, name string) (*yamlv3.Node, error) {
-	for _, mappingNode := range sequenceNode.Content {
-		nameOfNode, err := i.Name(mappingNode)
-		if err != nil {
-			return nil, err
-		}
-
-		if nameOfNode == name {
-			return mappingNode, nil
-		}
-	}
-
-	return nil, fmt.Errorf("failed to find mapping entry with name %q", name)
-}
-
 func (i *k8sItemIdentifier) Name(node *yamlv3.Node) (string, error) {
 	if node.Kind != yamlv3.MappingNode {
 		return "", fmt.Errorf("provided node is not a mapping node")
@@ -191,3 +158,44 @@ func K8sMetaFromName(name string) (*K8sMetadata, error) {
 
 	return nil, fmt.Errorf("invalid resource name %q", name)
 }
+
+// --- --- ---
+
+// compositeField is an list item identifier that relies on multiple fields to
+// differentiate between list items, e.g. 'containerPort' and 'protocol'
+type compositeField struct {
+	IdentifierFieldNames []string
+}
+
+var _ listItemIdentifier = &compositeField{}
+
+// Name returns the field names and values of the entry, for example
+// `containerPort=80,protocol=TCP`
+func (cf *compositeField) Name(mappingNode *yamlv3.Node) (string, error) {
+	parts := make([]string, len(cf.IdentifierFieldNames))
+	for i, fieldName := range cf.IdentifierFieldNames {
+		result, err := grab(mappingNode, fieldName)
+		if err != nil {
+			return "", err
+		}
+
+		parts[i] = fmt.Sprintf("%s=%s", fieldName, followAlias(result).Value)
+	}
+
+	return strings.Join(parts, ","), nil
+}
+
+func (cf *compositeField) String() string {
+	return strings.Join(cf.IdentifierFieldNames, ",")
+}
+
+// newPathWithListItem returns a new path for the list item with the given name,
+// since the name of a composite identifier already contains the field names,
+// it is used as-is in the path
+func newPathWithListItem(path ytbx.Path, identifier listItemIdentifier, name string) ytbx.Path {
+	if _, ok := identifier.(*compositeField); ok {
+		return ytbx.NewPathWithNamedElement(path, name)
+	}
+
+	return ytbx.NewPathWithNamedListElement(path, identifier, name)
+}
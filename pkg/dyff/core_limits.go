@@ -0,0 +1,194 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"fmt"
+	"math"
+	"runtime/debug"
+
+	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// Limit is the kind of limit that was exceeded by an input
+type Limit string
+
+// Limits that can be set for inputs of a comparison
+const (
+	LimitDepth           Limit = "depth"
+	LimitNodes           Limit = "number of nodes"
+	LimitAliasExpansions Limit = "number of alias expansions"
+)
+
+// LimitError is returned in case an input exceeds one of the limits set using
+// MaxDepth, MaxNodes, or MaxAliasExpansions
+type LimitError struct {
+	Location string
+	Limit    Limit
+	Max      int
+}
+
+func (err *LimitError) Error() string {
+	return fmt.Sprintf("%s exceeds the maximum %s of %d", err.Location, err.Limit, err.Max)
+}
+
+// PanicError is returned in case the comparison failed unexpectedly, it
+// contains the value of the recovered panic and the stack trace
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (err *PanicError) Error() string {
+	return fmt.Sprintf("unexpected failure while comparing: %v", err.Value)
+}
+
+// Unwrap returns the value of the recovered panic in case it is an error
+func (err *PanicError) Unwrap() error {
+	if cause, ok := err.Value.(error); ok {
+		return cause
+	}
+
+	return nil
+}
+
+// recoverPanic converts a panic into a PanicError, it has to be deferred
+func recoverPanic(err *error) {
+	if value := recover(); value != nil {
+		*err = &PanicError{Value: value, Stack: debug.Stack()}
+	}
+}
+
+// nodeStats are the properties of a node with all of its aliases expanded
+type nodeStats struct {
+	depth   int
+	nodes   uint64
+	aliases uint64
+}
+
+// statsCalculator calculates the node stats without actually expanding any
+// aliases, since the stats of anchored content are only calculated once
+type statsCalculator struct {
+	stats      map[*yamlv3.Node]nodeStats
+	inProgress map[*yamlv3.Node]struct{}
+}
+
+func (calculator *statsCalculator) calculate(node *yamlv3.Node) (nodeStats, error) {
+	if result, ok := calculator.stats[node]; ok {
+		return result, nil
+	}
+
+	if _, ok := calculator.inProgress[node]; ok {
+		return nodeStats{}, fmt.Errorf("alias refers to content that contains the alias itself")
+	}
+
+	calculator.inProgress[node] = struct{}{}
+	defer delete(calculator.inProgress, node)
+
+	var result nodeStats
+	if node.Kind == yamlv3.AliasNode && node.Alias != nil {
+		target, err := calculator.calculate(node.Alias)
+		if err != nil {
+			return nodeStats{}, err
+		}
+
+		result = target
+		result.aliases = saturatedAdd(result.aliases, 1)
+
+	} else {
+		for _, child := range node.Content {
+			stats, err := calculator.calculate(child)
+			if err != nil {
+				return nodeStats{}, err
+			}
+
+			result.depth = max(result.depth, stats.depth)
+			result.nodes = saturatedAdd(result.nodes, stats.nodes)
+			result.aliases = saturatedAdd(result.aliases, stats.aliases)
+		}
+
+		result.depth++
+		result.nodes = saturatedAdd(result.nodes, 1)
+	}
+
+	calculator.stats[node] = result
+	return result, nil
+}
+
+func saturatedAdd(a, b uint64) uint64 {
+	if a > math.MaxUint64-b {
+		return math.MaxUint64
+	}
+
+	return a + b
+}
+
+// checkLimits verifies that the documents of the input file do not exceed the
+// configured limits
+func (compare *compare) checkLimits(inputFile ytbx.InputFile) error {
+	location := inputFile.Location
+	if location == "" {
+		location = "input"
+	}
+
+	return compare.checkDocumentLimits(location, inputFile.Documents...)
+}
+
+func (compare *compare) checkDocumentLimits(location string, documents ...*yamlv3.Node) error {
+	settings := compare.settings
+	if settings.MaxDepth <= 0 && settings.MaxNodes <= 0 && settings.MaxAliasExpansions <= 0 {
+		return nil
+	}
+
+	calculator := &statsCalculator{
+		stats:      map[*yamlv3.Node]nodeStats{},
+		inProgress: map[*yamlv3.Node]struct{}{},
+	}
+
+	var total nodeStats
+	for _, document := range documents {
+		stats, err := calculator.calculate(document)
+		if err != nil {
+			return fmt.Errorf("%s is invalid: %w", location, err)
+		}
+
+		total.depth = max(total.depth, stats.depth)
+		total.nodes = saturatedAdd(total.nodes, stats.nodes)
+		total.aliases = saturatedAdd(total.aliases, stats.aliases)
+	}
+
+	for _, check := range []struct {
+		limit Limit
+		max   int
+		value uint64
+	}{
+		{LimitDepth, settings.MaxDepth, uint64(total.depth)},
+		{LimitNodes, settings.MaxNodes, total.nodes},
+		{LimitAliasExpansions, settings.MaxAliasExpansions, total.aliases},
+	} {
+		if check.max > 0 && check.value > uint64(check.max) {
+			return &LimitError{Location: location, Limit: check.limit, Max: check.max}
+		}
+	}
+
+	return nil
+}
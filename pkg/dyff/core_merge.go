@@ -0,0 +1,189 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+func isMergeKey(node *yamlv3.Node) bool {
+	return node.Kind == yamlv3.ScalarNode && node.Tag == "!!merge"
+}
+
+// mergeSources returns the maps that are merged into the map using the given
+// merge key value, which is either one alias or a list of aliases
+func mergeSources(value *yamlv3.Node) []*yamlv3.Node {
+	var result []*yamlv3.Node
+	switch value.Kind {
+	case yamlv3.SequenceNode:
+		for _, entry := range value.Content {
+			result = append(result, mergeSources(entry)...)
+		}
+
+	default:
+		if source := followAlias(value); source.Kind == yamlv3.MappingNode {
+			result = append(result, source)
+		}
+	}
+
+	return result
+}
+
+// resolveMergeKeys returns a copy of the map where the merge keys are replaced
+// with the entries of the merged maps. Entries of the map itself take
+// precedence over merged entries, and entries of earlier merged maps take
+// precedence over entries of later ones. The original node stays untouched.
+func resolveMergeKeys(node *yamlv3.Node) *yamlv3.Node {
+	var hasMergeKey bool
+	explicit := map[string]struct{}{}
+	for i := 0; i < len(node.Content); i += 2 {
+		if isMergeKey(node.Content[i]) {
+			hasMergeKey = true
+			continue
+		}
+
+		explicit[followAlias(node.Content[i]).Value] = struct{}{}
+	}
+
+	if !hasMergeKey {
+		return node
+	}
+
+	result := *node
+	result.Content = make([]*yamlv3.Node, 0, len(node.Content))
+	merged := map[string]struct{}{}
+	for i := 0; i < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if !isMergeKey(key) {
+			result.Content = append(result.Content, key, value)
+			continue
+		}
+
+		for _, source := range mergeSources(value) {
+			source = resolveMergeKeys(source)
+			for j := 0; j < len(source.Content); j += 2 {
+				name := followAlias(source.Content[j]).Value
+				_, isExplicit := explicit[name]
+				_, isMerged := merged[name]
+				if isExplicit || isMerged {
+					continue
+				}
+
+				merged[name] = struct{}{}
+				result.Content = append(result.Content, source.Content[j], source.Content[j+1])
+			}
+		}
+	}
+
+	return &result
+}
+
+// mergeKeyChanges returns a change in case the aliases used by the merge keys
+// of both maps differ
+func mergeKeyChanges(from, to *yamlv3.Node) []Detail {
+	aliases := func(node *yamlv3.Node) string {
+		var result string
+		for i := 0; i < len(node.Content); i += 2 {
+			if !isMergeKey(node.Content[i]) {
+				continue
+			}
+
+			value := node.Content[i+1]
+			entries := []*yamlv3.Node{value}
+			if value.Kind == yamlv3.SequenceNode {
+				entries = value.Content
+			}
+
+			for _, entry := range entries {
+				if entry.Kind == yamlv3.AliasNode {
+					if result != "" {
+						result += ", "
+					}
+
+					result += "*" + entry.Value
+				}
+			}
+		}
+
+		if result == "" {
+			return ""
+		}
+
+		return "merge " + result
+	}
+
+	return anchorDetails(aliases(from), aliases(to))
+}
+
+// anchorAndAliasChanges returns a change in case the anchor of the values is
+// different, or in case one of the values is an alias and the other is not, or
+// both are aliases of different anchors
+func anchorAndAliasChanges(from, to *yamlv3.Node) []Detail {
+	describe := func(node *yamlv3.Node) string {
+		switch {
+		case node.Kind == yamlv3.AliasNode:
+			return "alias *" + node.Value
+
+		case node.Anchor != "":
+			return "anchor &" + node.Anchor
+		}
+
+		return ""
+	}
+
+	return anchorDetails(describe(from), describe(to))
+}
+
+// withAnchorChanges reports the changes of the anchors and aliases of the two
+// nodes as a separate difference in front of the differences of their values
+func (compare *compare) withAnchorChanges(path ytbx.Path, from, to *yamlv3.Node, diffs []Diff) []Diff {
+	if !compare.settings.ReportAnchorChanges {
+		return diffs
+	}
+
+	details := anchorAndAliasChanges(from, to)
+	if len(details) == 0 {
+		return diffs
+	}
+
+	return append([]Diff{{Path: &path, Details: details}}, diffs...)
+}
+
+func anchorDetails(from, to string) []Detail {
+	if from == to {
+		return nil
+	}
+
+	node := func(description string) *yamlv3.Node {
+		if description == "" {
+			return nil
+		}
+
+		return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: description}
+	}
+
+	return []Detail{{
+		Kind: ANCHOR,
+		From: node(from),
+		To:   node(to),
+	}}
+}
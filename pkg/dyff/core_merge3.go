@@ -0,0 +1,1035 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// MergeChangeKind classifies a path of a three-way merge by the input that
+// changed it compared to the common base
+type MergeChangeKind int
+
+// Kinds of changes of a three-way merge
+const (
+	ChangedInOurs MergeChangeKind = iota
+	ChangedInTheirs
+	ChangedInBoth
+	Conflicting
+)
+
+func (kind MergeChangeKind) String() string {
+	switch kind {
+	case ChangedInOurs:
+		return "ours"
+
+	case ChangedInTheirs:
+		return "theirs"
+
+	case ChangedInBoth:
+		return "both"
+
+	case Conflicting:
+		return "conflict"
+	}
+
+	return "unknown"
+}
+
+// MergeChange is a path that was changed by at least one side of a three-way
+// merge, with the values of the base and both sides (nil in case of an absent
+// value). Paths refer to the base input file, except for added documents.
+type MergeChange struct {
+	Path   *ytbx.Path
+	Kind   MergeChangeKind
+	Base   *yamlv3.Node
+	Ours   *yamlv3.Node
+	Theirs *yamlv3.Node
+}
+
+// MergeResult is the result of a three-way merge: The merged documents and the
+// classified changes. In case of conflicts, the merged documents contain the
+// value of ours for each conflicting path.
+type MergeResult struct {
+	Base      ytbx.InputFile
+	Ours      ytbx.InputFile
+	Theirs    ytbx.InputFile
+	Documents []*yamlv3.Node
+	Changes   []MergeChange
+}
+
+// Conflicts returns the changes of both sides that could not be merged
+func (result MergeResult) Conflicts() []MergeChange {
+	var conflicts []MergeChange
+	for _, change := range result.Changes {
+		if change.Kind == Conflicting {
+			conflicts = append(conflicts, change)
+		}
+	}
+
+	return conflicts
+}
+
+// Merge3 merges the changes of two input files (ours and theirs) made to their
+// common base. Both sides are compared with the base using CompareInputFiles
+// to find the corresponding documents, map entries, and list entries, changes
+// made by only one side are taken over, changes made by both sides are
+// conflicts unless they are identical. The compare options are used for all
+// comparisons, for example to configure how list entries are identified.
+func Merge3(base, ours, theirs ytbx.InputFile, compareOptions ...CompareOption) (MergeResult, error) {
+	return merge3(base, ours, theirs, false, compareOptions...)
+}
+
+// Merge3WithConflictMarkers merges like Merge3, but the value of ours at each
+// conflicting path is surrounded by conflict markers, which also show the
+// value of theirs. The markers are YAML comments, the documents remain valid.
+func Merge3WithConflictMarkers(base, ours, theirs ytbx.InputFile, compareOptions ...CompareOption) (MergeResult, error) {
+	return merge3(base, ours, theirs, true, compareOptions...)
+}
+
+func merge3(base, ours, theirs ytbx.InputFile, markConflicts bool, compareOptions ...CompareOption) (MergeResult, error) {
+	oursReport, err := CompareInputFiles(base, ours, compareOptions...)
+	if err != nil {
+		return MergeResult{}, fmt.Errorf("failed to compare base with ours: %w", err)
+	}
+
+	theirsReport, err := CompareInputFiles(base, theirs, compareOptions...)
+	if err != nil {
+		return MergeResult{}, fmt.Errorf("failed to compare base with theirs: %w", err)
+	}
+
+	if len(oursReport.From.Documents) != len(theirsReport.From.Documents) {
+		return MergeResult{}, fmt.Errorf("failed to match the documents of ours and theirs with the documents of the base")
+	}
+
+	result := MergeResult{
+		Base:   oursReport.From,
+		Ours:   oursReport.To,
+		Theirs: theirsReport.To,
+	}
+
+	merger := &merger{
+		compare:       newCompare(context.Background(), compareOptions...),
+		markConflicts: markConflicts,
+	}
+
+	if err := merger.documents(&result, oursReport.documentCounterparts(), theirsReport.documentCounterparts()); err != nil {
+		return MergeResult{}, err
+	}
+
+	result.Changes = merger.changes
+	return result, nil
+}
+
+// merger collects the changes while merging the nodes of two inputs
+type merger struct {
+	compare       *compare
+	markConflicts bool
+	changes       []MergeChange
+}
+
+// documents merges the documents of both sides, the order of the documents of
+// ours is kept unless only theirs changed the order, documents that were added
+// by one side are placed after the document that precedes them there
+func (merger *merger) documents(result *MergeResult, oursIdx []int, theirsIdx []int) error {
+	base, ours, theirs := &result.Base, &result.Ours, &result.Theirs
+
+	// documents are referred to by their index in the base, or in case of added
+	// documents, by their index in the input file that added them
+	oursKeys := make([]documentKey, len(ours.Documents))
+	for j := range ours.Documents {
+		oursKeys[j] = documentKey{ours, j}
+	}
+
+	theirsKeys := make([]documentKey, len(theirs.Documents))
+	for k := range theirs.Documents {
+		theirsKeys[k] = documentKey{theirs, k}
+	}
+
+	baseKeys := make([]documentKey, len(base.Documents))
+	oursIndex, theirsIndex := map[documentKey]*yamlv3.Node{}, map[documentKey]*yamlv3.Node{}
+	for i := range base.Documents {
+		baseKeys[i] = documentKey{base, i}
+
+		if j := oursIdx[i]; j >= 0 {
+			oursKeys[j] = baseKeys[i]
+			oursIndex[baseKeys[i]] = ours.Documents[j]
+		}
+
+		if k := theirsIdx[i]; k >= 0 {
+			theirsKeys[k] = baseKeys[i]
+			theirsIndex[baseKeys[i]] = theirs.Documents[k]
+		}
+	}
+
+	primary, secondary := oursKeys, theirsKeys
+	baseOrder := namesIn(baseKeys, oursIndex, theirsIndex)
+	if slices.Equal(baseOrder, namesIn(oursKeys, oursIndex, theirsIndex)) && !slices.Equal(baseOrder, namesIn(theirsKeys, oursIndex, theirsIndex)) {
+		primary, secondary = theirsKeys, oursKeys
+	}
+
+	// documents added by both sides are only added once
+	duplicates := map[documentKey]struct{}{}
+	for j, oursKey := range oursKeys {
+		for k, theirsKey := range theirsKeys {
+			if oursKey.file != ours || theirsKey.file != theirs {
+				continue
+			}
+
+			same, err := merger.equal(ytbx.Path{Root: ours, DocumentIdx: j}, documentContent(ours.Documents[j]), documentContent(theirs.Documents[k]))
+			if err != nil {
+				return err
+			}
+
+			if same {
+				duplicates[oursKey], duplicates[theirsKey] = struct{}{}, struct{}{}
+			}
+		}
+	}
+
+	// conflict markers of documents that were removed by ours are placed in
+	// front of the next document
+	var pending string
+	add := func(document *yamlv3.Node) {
+		if pending != "" {
+			document = withHeadComment(document, pending)
+			pending = ""
+		}
+
+		result.Documents = append(result.Documents, document)
+	}
+
+	for _, key := range arrangeNames(primary, secondary) {
+		path, document := ytbx.Path{Root: key.file, DocumentIdx: key.idx}, key.file.Documents[key.idx]
+		_, duplicate := duplicates[key]
+
+		switch {
+		case key.file == ours && duplicate:
+			merger.record(path, ChangedInBoth, nil, documentContent(document), documentContent(document))
+			add(document)
+
+		case key.file == ours:
+			merger.record(path, ChangedInOurs, nil, documentContent(document), nil)
+			add(document)
+
+		case key.file == theirs && !duplicate:
+			merger.record(path, ChangedInTheirs, nil, nil, documentContent(document))
+			add(document)
+
+		case key.file == base:
+			oursDocument, theirsDocument := oursIndex[key], theirsIndex[key]
+			since := len(merger.changes)
+			merged, err := merger.merge(path, documentContent(document), documentContent(oursDocument), documentContent(theirsDocument))
+			if err != nil {
+				return err
+			}
+
+			start, end, err := merger.conflictMarkersAt(path, since, func(value *yamlv3.Node) *yamlv3.Node {
+				return value
+			})
+			if err != nil {
+				return err
+			}
+
+			if merged == nil {
+				pending = joinComments(pending, start, end)
+				continue
+			}
+
+			// keep the document comments of the respective side
+			mergedDocument := theirsDocument
+			if oursDocument != nil {
+				mergedDocument = oursDocument
+			}
+
+			document := *mergedDocument
+			document.Content = []*yamlv3.Node{merged}
+			document.HeadComment = joinComments(start, document.HeadComment)
+			document.FootComment = joinComments(document.FootComment, end)
+
+			add(&document)
+		}
+	}
+
+	// trailing conflict markers are placed after the last document, or in an
+	// empty document, since a document cannot consist of comments only
+	switch {
+	case pending != "" && len(result.Documents) > 0:
+		last := len(result.Documents) - 1
+		result.Documents[last] = withFootComment(result.Documents[last], pending)
+
+	case pending != "":
+		add(&yamlv3.Node{
+			Kind:    yamlv3.DocumentNode,
+			Content: []*yamlv3.Node{{Kind: yamlv3.ScalarNode, Tag: "!!null"}},
+		})
+	}
+
+	// documents that were removed by both sides
+	for i := range base.Documents {
+		if oursIdx[i] < 0 && theirsIdx[i] < 0 {
+			merger.record(ytbx.Path{Root: base, DocumentIdx: i}, ChangedInBoth, documentContent(base.Documents[i]), nil, nil)
+		}
+	}
+
+	// the merged documents share nodes with the inputs, which must not change
+	for i, document := range result.Documents {
+		result.Documents[i] = cloneNode(document, map[*yamlv3.Node]*yamlv3.Node{})
+		relinkAliases(result.Documents[i])
+	}
+
+	return nil
+}
+
+// documentKey refers to a document of one of the input files of the merge
+type documentKey struct {
+	file *ytbx.InputFile
+	idx  int
+}
+
+func (merger *merger) record(path ytbx.Path, kind MergeChangeKind, base, ours, theirs *yamlv3.Node) {
+	merger.changes = append(merger.changes, MergeChange{
+		Path:   &path,
+		Kind:   kind,
+		Base:   base,
+		Ours:   ours,
+		Theirs: theirs,
+	})
+}
+
+// conflictMarkersAt returns the conflict markers that precede and follow the
+// value of ours in case a conflict was recorded for the given path since the
+// given number of changes, conflicts of nested paths are not considered. The
+// value of theirs is shown the way it is returned by the entry function.
+func (merger *merger) conflictMarkersAt(path ytbx.Path, since int, entry func(*yamlv3.Node) *yamlv3.Node) (string, string, error) {
+	for _, change := range merger.changes[since:] {
+		if change.Kind == Conflicting && change.Path.DocumentIdx == path.DocumentIdx && slices.Equal(change.Path.PathElements, path.PathElements) {
+			var theirs *yamlv3.Node
+			if change.Theirs != nil {
+				theirs = entry(change.Theirs)
+			}
+
+			return merger.conflictMarkers(theirs)
+		}
+	}
+
+	return "", "", nil
+}
+
+// equal returns whether both nodes are equal based on the compare settings,
+// where nil refers to an absent value
+func (merger *merger) equal(path ytbx.Path, a, b *yamlv3.Node) (bool, error) {
+	if a == nil || b == nil {
+		return a == nil && b == nil, nil
+	}
+
+	diffs, err := merger.compare.objects(path, followAlias(a), followAlias(b))
+	if err != nil {
+		return false, err
+	}
+
+	return len(diffs) == 0, nil
+}
+
+// merge returns the merged value of the base, ours, and theirs value at the
+// given path, where nil refers to an absent value, which is also the result
+// in case the value is removed. Maps and lists that were changed by both
+// sides are merged entry by entry.
+func (merger *merger) merge(path ytbx.Path, base, ours, theirs *yamlv3.Node) (*yamlv3.Node, error) {
+	since := len(merger.changes)
+	oursUnchanged, err := merger.equal(path, base, ours)
+	if err != nil {
+		return nil, err
+	}
+
+	theirsUnchanged, err := merger.equal(path, base, theirs)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case oursUnchanged && theirsUnchanged:
+		return ours, nil
+
+	case theirsUnchanged:
+		merger.record(path, ChangedInOurs, base, ours, theirs)
+		return ours, nil
+
+	case oursUnchanged:
+		merger.record(path, ChangedInTheirs, base, ours, theirs)
+		return theirs, nil
+	}
+
+	same, err := merger.equal(path, ours, theirs)
+	if err != nil {
+		return nil, err
+	}
+
+	if same {
+		merger.record(path, ChangedInBoth, base, ours, theirs)
+		return ours, nil
+	}
+
+	if base != nil && ours != nil && theirs != nil {
+		var merged *yamlv3.Node
+		switch b, o, t := followAlias(base), followAlias(ours), followAlias(theirs); {
+		case b.Kind == yamlv3.MappingNode && o.Kind == yamlv3.MappingNode && t.Kind == yamlv3.MappingNode:
+			merged, err = merger.mappingNodes(path, b, o, t)
+
+		case b.Kind == yamlv3.SequenceNode && o.Kind == yamlv3.SequenceNode && t.Kind == yamlv3.SequenceNode:
+			merged, err = merger.sequenceNodes(path, b, o, t)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if merged != nil {
+			// the merged content of an alias must not redefine the anchor
+			if ours.Kind == yamlv3.AliasNode {
+				merged.Anchor = ""
+			}
+
+			// conflict markers cannot be placed within flow style maps and lists
+			conflicting := slices.ContainsFunc(merger.changes[since:], func(change MergeChange) bool {
+				return change.Kind == Conflicting
+			})
+
+			if merger.markConflicts && conflicting {
+				merged.Style &^= yamlv3.FlowStyle
+			}
+
+			return merged, nil
+		}
+	}
+
+	merger.record(path, Conflicting, base, ours, theirs)
+	return ours, nil
+}
+
+// mappingNodes merges the entries of three maps, the order of the keys of
+// ours is kept, keys that were only added by theirs are placed after the key
+// that precedes them in theirs
+func (merger *merger) mappingNodes(path ytbx.Path, base, ours, theirs *yamlv3.Node) (*yamlv3.Node, error) {
+	result := *ours
+	result.Content = make([]*yamlv3.Node, 0, len(ours.Content))
+
+	for i := 0; i < len(ours.Content); i += 2 {
+		key := followAlias(ours.Content[i]).Value
+		_, baseValue, _ := findEntry(base, key)
+		theirsKey, theirsValue, _ := findEntry(theirs, key)
+
+		since, entryPath := len(merger.changes), ytbx.NewPathWithNamedElement(path, key)
+		value, err := merger.merge(entryPath, baseValue, ours.Content[i+1], theirsValue)
+		if err != nil {
+			return nil, err
+		}
+
+		start, end, err := merger.conflictMarkersAt(entryPath, since, func(value *yamlv3.Node) *yamlv3.Node {
+			return mappingEntry(theirsKey, value)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if value != nil {
+			result.Content = append(result.Content, withFootComment(withHeadComment(ours.Content[i], start), end), value)
+		}
+	}
+
+	var predecessor string
+	for i := 0; i < len(theirs.Content); i += 2 {
+		key := followAlias(theirs.Content[i]).Value
+		if _, _, ok := findEntry(ours, key); ok {
+			predecessor = key
+			continue
+		}
+
+		_, baseValue, _ := findEntry(base, key)
+		since, entryPath := len(merger.changes), ytbx.NewPathWithNamedElement(path, key)
+		value, err := merger.merge(entryPath, baseValue, nil, theirs.Content[i+1])
+		if err != nil {
+			return nil, err
+		}
+
+		idx := 0
+		if predecessor != "" {
+			idx = mappingKeyIndex(&result, predecessor) + 2
+		}
+
+		if value == nil {
+			// ours removed the entry that theirs changed
+			start, end, err := merger.conflictMarkersAt(entryPath, since, func(value *yamlv3.Node) *yamlv3.Node {
+				return mappingEntry(theirs.Content[i], value)
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			markAbsentValue(&result, idx, 2, joinComments(start, end))
+			continue
+		}
+
+		result.Content = slices.Insert(result.Content, idx, theirs.Content[i], value)
+		predecessor = key
+	}
+
+	// keys that were removed by both sides
+	for i := 0; i < len(base.Content); i += 2 {
+		key := followAlias(base.Content[i]).Value
+		_, _, inOurs := findEntry(ours, key)
+		_, _, inTheirs := findEntry(theirs, key)
+		if !inOurs && !inTheirs {
+			merger.record(ytbx.NewPathWithNamedElement(path, key), ChangedInBoth, base.Content[i+1], nil, nil)
+		}
+	}
+
+	return &result, nil
+}
+
+// sequenceNodes merges the entries of three lists, entries are matched by
+// their identifier if possible, otherwise the lists are merged by position
+func (merger *merger) sequenceNodes(path ytbx.Path, base, ours, theirs *yamlv3.Node) (*yamlv3.Node, error) {
+	if identifier := merger.listIdentifier(path, base, ours, theirs); identifier != nil {
+		return merger.namedEntryLists(path, identifier, base, ours, theirs)
+	}
+
+	return merger.simpleLists(path, base, ours, theirs)
+}
+
+// listIdentifier returns the identifier of the list entries, if it identifies
+// the entries of all three lists uniquely
+func (merger *merger) listIdentifier(path ytbx.Path, base, ours, theirs *yamlv3.Node) listItemIdentifier {
+	identifier := merger.compare.listIdentifier(path, ours, theirs)
+	if identifier == nil {
+		return nil
+	}
+
+	for _, list := range []*yamlv3.Node{base, ours, theirs} {
+		names, _, err := entriesByName(identifier, list)
+		if err != nil {
+			return nil
+		}
+
+		unique := map[string]struct{}{}
+		for _, name := range names {
+			unique[name] = struct{}{}
+		}
+
+		if len(unique) != len(names) {
+			return nil
+		}
+	}
+
+	return identifier
+}
+
+// namedEntryLists merges lists with identifiable entries, the order of the
+// entries of ours is kept unless only theirs changed the order
+func (merger *merger) namedEntryLists(path ytbx.Path, identifier listItemIdentifier, base, ours, theirs *yamlv3.Node) (*yamlv3.Node, error) {
+	baseNames, baseIndex, err := entriesByName(identifier, base)
+	if err != nil {
+		return nil, err
+	}
+
+	oursNames, oursIndex, err := entriesByName(identifier, ours)
+	if err != nil {
+		return nil, err
+	}
+
+	theirsNames, theirsIndex, err := entriesByName(identifier, theirs)
+	if err != nil {
+		return nil, err
+	}
+
+	primary, secondary := oursNames, theirsNames
+	if !merger.compare.ignoreOrderChangesAt(path) {
+		// the order of the entries that are part of all three lists
+		baseOrder := namesIn(baseNames, oursIndex, theirsIndex)
+		oursOrder := namesIn(oursNames, baseIndex, theirsIndex)
+		theirsOrder := namesIn(theirsNames, baseIndex, oursIndex)
+
+		oursReordered, theirsReordered := !slices.Equal(baseOrder, oursOrder), !slices.Equal(baseOrder, theirsOrder)
+		switch {
+		case theirsReordered && !oursReordered:
+			primary, secondary = theirsNames, oursNames
+
+		case theirsReordered && oursReordered && !slices.Equal(oursOrder, theirsOrder):
+			merger.record(path, Conflicting, base, ours, theirs)
+		}
+	}
+
+	result := *ours
+	result.Content = make([]*yamlv3.Node, 0, len(ours.Content))
+	absent := map[int]string{}
+	for _, name := range arrangeNames(primary, secondary) {
+		since, entryPath := len(merger.changes), newPathWithListItem(path, identifier, name)
+		value, err := merger.merge(entryPath, baseIndex[name], oursIndex[name], theirsIndex[name])
+		if err != nil {
+			return nil, err
+		}
+
+		start, end, err := merger.conflictMarkersAt(entryPath, since, func(value *yamlv3.Node) *yamlv3.Node {
+			return chunkNode([]*yamlv3.Node{value})
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		if value == nil {
+			absent[len(result.Content)] = joinComments(absent[len(result.Content)], start, end)
+			continue
+		}
+
+		result.Content = append(result.Content, withFootComment(withHeadComment(value, start), end))
+	}
+
+	for idx, markers := range absent {
+		markAbsentValue(&result, idx, 1, markers)
+	}
+
+	// entries that were removed by both sides
+	for _, name := range baseNames {
+		_, inOurs := oursIndex[name]
+		_, inTheirs := theirsIndex[name]
+		if !inOurs && !inTheirs {
+			merger.record(newPathWithListItem(path, identifier, name), ChangedInBoth, baseIndex[name], nil, nil)
+		}
+	}
+
+	return &result, nil
+}
+
+// simpleLists merges lists by position: Entries that are in the same order in
+// all three lists serve as anchors, the entries in between were changed by
+// one side, or both sides, which is a conflict unless both made the same change
+func (merger *merger) simpleLists(path ytbx.Path, base, ours, theirs *yamlv3.Node) (*yamlv3.Node, error) {
+	var hashes [3][]uint64
+	for i, list := range []*yamlv3.Node{base, ours, theirs} {
+		var err error
+		if hashes[i], err = merger.compare.listEntryHashes(path, list); err != nil {
+			return nil, err
+		}
+	}
+
+	baseHashes, oursHashes, theirsHashes := hashes[0], hashes[1], hashes[2]
+
+	oursLCS, err := longestCommonSubsequence(merger.compare.ctx, baseHashes, oursHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	oursMatches := map[int]int{}
+	for _, match := range oursLCS {
+		oursMatches[match[0]] = match[1]
+	}
+
+	theirsLCS, err := longestCommonSubsequence(merger.compare.ctx, baseHashes, theirsHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	theirsMatches := map[int]int{}
+	for _, match := range theirsLCS {
+		theirsMatches[match[0]] = match[1]
+	}
+
+	result := *ours
+	result.Content = make([]*yamlv3.Node, 0, len(ours.Content))
+	absent := map[int]string{}
+
+	var i, j, k int
+	for i < len(base.Content) || j < len(ours.Content) || k < len(theirs.Content) {
+		if oj, ok := oursMatches[i]; ok && oj == j {
+			if tk, ok := theirsMatches[i]; ok && tk == k {
+				result.Content = append(result.Content, ours.Content[j])
+				i, j, k = i+1, j+1, k+1
+				continue
+			}
+		}
+
+		// find the next base entry that is part of all three lists
+		m, oj, tk := i, len(ours.Content), len(theirs.Content)
+		for ; m < len(base.Content); m++ {
+			o, inOurs := oursMatches[m]
+			t, inTheirs := theirsMatches[m]
+			if inOurs && inTheirs {
+				oj, tk = o, t
+				break
+			}
+		}
+
+		baseChunk, oursChunk, theirsChunk := base.Content[i:m], ours.Content[j:oj], theirs.Content[k:tk]
+		chunkPath := ytbx.NewPathWithIndexedListElement(path, i)
+
+		switch oursUnchanged, theirsUnchanged := slices.Equal(baseHashes[i:m], oursHashes[j:oj]), slices.Equal(baseHashes[i:m], theirsHashes[k:tk]); {
+		case theirsUnchanged:
+			if !oursUnchanged {
+				merger.record(chunkPath, ChangedInOurs, chunkNode(baseChunk), chunkNode(oursChunk), chunkNode(theirsChunk))
+			}
+
+			result.Content = append(result.Content, oursChunk...)
+
+		case oursUnchanged:
+			merger.record(chunkPath, ChangedInTheirs, chunkNode(baseChunk), chunkNode(oursChunk), chunkNode(theirsChunk))
+			result.Content = append(result.Content, theirsChunk...)
+
+		case slices.Equal(oursHashes[j:oj], theirsHashes[k:tk]):
+			merger.record(chunkPath, ChangedInBoth, chunkNode(baseChunk), chunkNode(oursChunk), chunkNode(theirsChunk))
+			result.Content = append(result.Content, oursChunk...)
+
+		default:
+			merger.record(chunkPath, Conflicting, chunkNode(baseChunk), chunkNode(oursChunk), chunkNode(theirsChunk))
+
+			var theirsEntries *yamlv3.Node
+			if len(theirsChunk) > 0 {
+				theirsEntries = chunkNode(theirsChunk)
+			}
+
+			start, end, err := merger.conflictMarkers(theirsEntries)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(oursChunk) == 0 {
+				absent[len(result.Content)] = joinComments(absent[len(result.Content)], start, end)
+				break
+			}
+
+			entries := slices.Clone(oursChunk)
+			entries[0] = withHeadComment(entries[0], start)
+			entries[len(entries)-1] = withFootComment(entries[len(entries)-1], end)
+			result.Content = append(result.Content, entries...)
+		}
+
+		i, j, k = m, oj, tk
+	}
+
+	for idx, markers := range absent {
+		markAbsentValue(&result, idx, 1, markers)
+	}
+
+	return &result, nil
+}
+
+// namesIn returns the names that are also part of both indexes
+func namesIn[T comparable](names []T, a map[T]*yamlv3.Node, b map[T]*yamlv3.Node) []T {
+	var result []T
+	for _, name := range names {
+		_, inA := a[name]
+		_, inB := b[name]
+		if inA && inB {
+			result = append(result, name)
+		}
+	}
+
+	return result
+}
+
+// arrangeNames returns the primary names, where the names that are only part
+// of the secondary names are placed after the name that precedes them there
+func arrangeNames[T comparable](primary, secondary []T) []T {
+	result := append([]T{}, primary...)
+	positions := map[T]struct{}{}
+	for _, name := range primary {
+		positions[name] = struct{}{}
+	}
+
+	var idx int
+	for _, name := range secondary {
+		if _, ok := positions[name]; ok {
+			for i := range result {
+				if result[i] == name {
+					idx = i + 1
+					break
+				}
+			}
+
+			continue
+		}
+
+		result = slices.Insert(result, idx, name)
+		positions[name] = struct{}{}
+		idx++
+	}
+
+	return result
+}
+
+func chunkNode(entries []*yamlv3.Node) *yamlv3.Node {
+	return &yamlv3.Node{
+		Kind:    yamlv3.SequenceNode,
+		Tag:     "!!seq",
+		Content: entries,
+	}
+}
+
+func mappingEntry(key *yamlv3.Node, value *yamlv3.Node) *yamlv3.Node {
+	return &yamlv3.Node{
+		Kind:    yamlv3.MappingNode,
+		Tag:     "!!map",
+		Content: []*yamlv3.Node{key, value},
+	}
+}
+
+// conflictMarkers returns the conflict markers that precede and follow the
+// value of ours, the value of theirs (nil in case it is absent) is placed in
+// between, all as YAML comments so that the merged documents remain valid
+func (merger *merger) conflictMarkers(theirs *yamlv3.Node) (string, string, error) {
+	if !merger.markConflicts {
+		return "", "", nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("=======\n")
+
+	if theirs != nil {
+		encoder := yamlv3.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(copyNode(theirs, nil)); err != nil {
+			return "", "", fmt.Errorf("failed to render conflicting value of theirs: %w", err)
+		}
+
+		if err := encoder.Close(); err != nil {
+			return "", "", fmt.Errorf("failed to render conflicting value of theirs: %w", err)
+		}
+	}
+
+	buf.WriteString(">>>>>>> theirs")
+
+	// comment out each line explicitly, since empty lines would end the comment
+	lines := strings.Split(buf.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("# "+line, " ")
+	}
+
+	return "# <<<<<<< ours", strings.Join(lines, "\n"), nil
+}
+
+// joinComments joins the non-empty comments line by line
+func joinComments(comments ...string) string {
+	var result []string
+	for _, comment := range comments {
+		if comment != "" {
+			result = append(result, comment)
+		}
+	}
+
+	return strings.Join(result, "\n")
+}
+
+// withHeadComment returns a copy of the node with the comment placed in front
+// of the existing head comment
+func withHeadComment(node *yamlv3.Node, comment string) *yamlv3.Node {
+	if comment == "" {
+		return node
+	}
+
+	result := *node
+	result.HeadComment = joinComments(comment, node.HeadComment)
+	return &result
+}
+
+// withFootComment returns a copy of the node with the comment placed after the
+// existing foot comment. Foot comments of block style maps and lists are placed
+// at their last entry, since they would otherwise end up in the following entry.
+func withFootComment(node *yamlv3.Node, comment string) *yamlv3.Node {
+	if comment == "" {
+		return node
+	}
+
+	result := *node
+	switch {
+	case node.Kind == yamlv3.MappingNode && node.Style&yamlv3.FlowStyle == 0 && len(node.Content) > 0:
+		result.Content = slices.Clone(node.Content)
+		result.Content[len(node.Content)-2] = withFootComment(node.Content[len(node.Content)-2], comment)
+
+	case node.Kind == yamlv3.SequenceNode && node.Style&yamlv3.FlowStyle == 0 && len(node.Content) > 0:
+		result.Content = slices.Clone(node.Content)
+		result.Content[len(node.Content)-1] = withFootComment(node.Content[len(node.Content)-1], comment)
+
+	default:
+		result.FootComment = joinComments(node.FootComment, comment)
+	}
+
+	return &result
+}
+
+// markAbsentValue adds the conflict markers of a value that was removed by
+// ours to the entry at the given index, where the value would be placed, to
+// the preceding entry in case there is none, or to the container itself
+func markAbsentValue(container *yamlv3.Node, idx int, width int, markers string) {
+	switch {
+	case markers == "":
+		return
+
+	case idx < len(container.Content):
+		container.Content[idx] = withHeadComment(container.Content[idx], markers)
+
+	case idx >= width:
+		container.Content[idx-width] = withFootComment(container.Content[idx-width], markers)
+
+	default:
+		container.FootComment = joinComments(container.FootComment, markers)
+	}
+}
+
+// relinkAliases lets aliases refer to the last preceding anchor with the same
+// name, since the merged document consists of nodes of different inputs,
+// aliases without such an anchor are replaced with a copy of their content
+func relinkAliases(document *yamlv3.Node) {
+	anchors := map[string]*yamlv3.Node{}
+
+	var walk func(node *yamlv3.Node)
+	walk = func(node *yamlv3.Node) {
+		if node.Kind == yamlv3.AliasNode {
+			if anchor, ok := anchors[node.Value]; ok {
+				node.Alias = anchor
+			} else {
+				*node = *copyNode(node, nil)
+			}
+
+			return
+		}
+
+		if node.Anchor != "" {
+			anchors[node.Anchor] = node
+		}
+
+		for _, child := range node.Content {
+			walk(child)
+		}
+	}
+
+	walk(document)
+}
+
+// renamedDocuments pairs the documents that have no document with the same
+// name in the other input file, but were compared as renamed documents. The
+// differences of a renamed document refer to nodes of its counterpart.
+func (report Report) renamedDocuments(result []int, paired map[int]struct{}) {
+	documents := map[*yamlv3.Node]int{}
+	var walk func(node *yamlv3.Node, idx int)
+	walk = func(node *yamlv3.Node, idx int) {
+		documents[node] = idx
+		for _, child := range node.Content {
+			walk(child, idx)
+		}
+	}
+
+	for j, document := range report.To.Documents {
+		if _, ok := paired[j]; !ok {
+			walk(document, j)
+		}
+	}
+
+	for _, diff := range report.Diffs {
+		if diff.Path == nil || isDocumentChange(diff) || diff.Path.DocumentIdx >= len(result) || result[diff.Path.DocumentIdx] >= 0 {
+			continue
+		}
+
+		for _, detail := range diff.Details {
+			if j, ok := documents[detail.To]; ok {
+				if _, ok := paired[j]; !ok {
+					result[diff.Path.DocumentIdx], paired[j] = j, struct{}{}
+				}
+
+				break
+			}
+		}
+	}
+}
+
+// documentCounterparts returns the index of the document of the `to` input
+// file for each document of the `from` input file, or -1 if it was removed
+func (report Report) documentCounterparts() []int {
+	from, to := report.From, report.To
+	result := make([]int, len(from.Documents))
+	for i := range result {
+		result[i] = -1
+	}
+
+	switch {
+	case len(from.Names) == len(from.Documents) && len(to.Names) == len(to.Documents):
+		paired := map[int]struct{}{}
+		for i, name := range from.Names {
+			for j := range to.Names {
+				if to.Names[j] == name {
+					result[i], paired[j] = j, struct{}{}
+					break
+				}
+			}
+		}
+
+		report.renamedDocuments(result, paired)
+
+	case len(from.Documents) == len(to.Documents):
+		for i := range result {
+			result[i] = i
+		}
+
+	default:
+		// documents were aligned, pair the documents in order except for the
+		// documents that were reported as removed or added
+		removed, added := map[int]struct{}{}, map[int]struct{}{}
+		for _, diff := range report.Diffs {
+			if diff.Path == nil || !isDocumentChange(diff) {
+				continue
+			}
+
+			for _, detail := range diff.Details {
+				switch detail.Kind {
+				case REMOVAL:
+					removed[diff.Path.DocumentIdx] = struct{}{}
+
+				case ADDITION:
+					added[diff.Path.DocumentIdx] = struct{}{}
+				}
+			}
+		}
+
+		for i, j := 0, 0; i < len(from.Documents) && j < len(to.Documents); i++ {
+			if _, ok := removed[i]; ok {
+				continue
+			}
+
+			for ; j < len(to.Documents); j++ {
+				if _, ok := added[j]; !ok {
+					break
+				}
+			}
+
+			if j < len(to.Documents) {
+				result[i] = j
+				j++
+			}
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,393 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff_test
+
+import (
+	"bytes"
+
+	. "github.com/gonvenience/bunt"
+	"github.com/gonvenience/ytbx"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/homeport/dyff/pkg/dyff"
+)
+
+var _ = Describe("three-way merge", func() {
+	input := func(location string, data string) ytbx.InputFile {
+		documents, err := ytbx.LoadYAMLDocuments([]byte(data))
+		Expect(err).ToNot(HaveOccurred())
+		return ytbx.InputFile{Location: location, Documents: documents}
+	}
+
+	merge := func(base, ours, theirs string, compareOptions ...dyff.CompareOption) dyff.MergeResult {
+		result, err := dyff.Merge3(input("base", base), input("ours", ours), input("theirs", theirs), compareOptions...)
+		Expect(err).ToNot(HaveOccurred())
+		return result
+	}
+
+	output := func(result dyff.MergeResult) string {
+		var buf bytes.Buffer
+		for i, document := range result.Documents {
+			if i > 0 {
+				buf.WriteString("---\n")
+			}
+
+			encoder := yamlv3.NewEncoder(&buf)
+			encoder.SetIndent(2)
+			Expect(encoder.Encode(document)).To(Succeed())
+			Expect(encoder.Close()).To(Succeed())
+		}
+
+		return buf.String()
+	}
+
+	classification := func(result dyff.MergeResult) map[string]dyff.MergeChangeKind {
+		changes := map[string]dyff.MergeChangeKind{}
+		for _, change := range result.Changes {
+			changes[change.Path.String()] = change.Kind
+		}
+
+		return changes
+	}
+
+	Context("merging maps", func() {
+		It("should take over changes of both sides at different paths", func() {
+			result := merge(`
+name: app
+replicas: 1
+image: nginx:1.0
+`, `
+name: app
+replicas: 3
+image: nginx:1.0
+`, `
+name: app
+replicas: 1
+image: nginx:1.1
+port: 80
+`)
+
+			Expect(result.Conflicts()).To(BeEmpty())
+			Expect(output(result)).To(Equal(`name: app
+replicas: 3
+image: nginx:1.1
+port: 80
+`))
+			Expect(classification(result)).To(Equal(map[string]dyff.MergeChangeKind{
+				"/replicas": dyff.ChangedInOurs,
+				"/image":    dyff.ChangedInTheirs,
+				"/port":     dyff.ChangedInTheirs,
+			}))
+		})
+
+		It("should accept identical changes of both sides", func() {
+			result := merge(`{a: 1, b: 2}`, `{a: 5, b: 2}`, `{a: 5, b: 3}`)
+			Expect(result.Conflicts()).To(BeEmpty())
+			Expect(output(result)).To(Equal("{a: 5, b: 3}\n"))
+			Expect(classification(result)).To(Equal(map[string]dyff.MergeChangeKind{
+				"/a": dyff.ChangedInBoth,
+				"/b": dyff.ChangedInTheirs,
+			}))
+		})
+
+		It("should report different changes of the same path as a conflict and keep ours", func() {
+			result := merge(`{a: {b: 1, c: 1}}`, `{a: {b: 2, c: 1}}`, `{a: {b: 3, c: 2}}`)
+			Expect(output(result)).To(Equal("{a: {b: 2, c: 2}}\n"))
+
+			conflicts := result.Conflicts()
+			Expect(conflicts).To(HaveLen(1))
+			Expect(conflicts[0].Path.String()).To(Equal("/a/b"))
+			Expect(conflicts[0].Base.Value).To(Equal("1"))
+			Expect(conflicts[0].Ours.Value).To(Equal("2"))
+			Expect(conflicts[0].Theirs.Value).To(Equal("3"))
+		})
+
+		It("should handle removals of keys", func() {
+			result := merge(`{a: 1, b: 2, c: 3, d: 4}`, `{b: 2, c: 3, d: 4}`, `{a: 1, b: 2, c: 5}`)
+			Expect(output(result)).To(Equal("{b: 2, c: 5}\n"))
+			Expect(result.Conflicts()).To(BeEmpty())
+
+			result = merge(`{a: 1, b: 2}`, `{b: 2}`, `{a: 5, b: 2}`)
+			Expect(output(result)).To(Equal("{b: 2}\n"))
+			Expect(result.Conflicts()).To(HaveLen(1))
+			Expect(result.Conflicts()[0].Ours).To(BeNil())
+		})
+
+		It("should keep comments and anchors of ours", func() {
+			result := merge(`# config
+defaults: &defaults
+  image: nginx:1.0
+app:
+  <<: *defaults
+  replicas: 1
+`, `# config
+defaults: &defaults
+  image: nginx:1.0 # the image
+app:
+  <<: *defaults
+  # scaled up
+  replicas: 3
+`, `defaults: &defaults
+  image: nginx:1.1
+app:
+  <<: *defaults
+  replicas: 1
+`)
+
+			Expect(result.Conflicts()).To(BeEmpty())
+			Expect(output(result)).To(Equal(`# config
+defaults: &defaults
+  image: nginx:1.1
+app:
+  !!merge <<: *defaults
+  # scaled up
+  replicas: 3
+`))
+		})
+	})
+
+	Context("merging lists", func() {
+		It("should merge lists with identifiable entries by their names", func() {
+			result := merge(`
+containers:
+- name: app
+  image: app:1.0
+- name: sidecar
+  image: proxy:1.0
+`, `
+containers:
+- name: app
+  image: app:2.0
+- name: sidecar
+  image: proxy:1.0
+- name: logger
+  image: logger:1.0
+`, `
+containers:
+- name: init
+  image: busybox
+- name: app
+  image: app:1.0
+- name: sidecar
+  image: proxy:1.1
+`)
+
+			Expect(result.Conflicts()).To(BeEmpty())
+			Expect(output(result)).To(Equal(`containers:
+  - name: init
+    image: busybox
+  - name: app
+    image: app:2.0
+  - name: sidecar
+    image: proxy:1.1
+  - name: logger
+    image: logger:1.0
+`))
+			Expect(classification(result)).To(Equal(map[string]dyff.MergeChangeKind{
+				"/containers/name=app":     dyff.ChangedInOurs,
+				"/containers/name=sidecar": dyff.ChangedInTheirs,
+				"/containers/name=init":    dyff.ChangedInTheirs,
+				"/containers/name=logger":  dyff.ChangedInOurs,
+			}))
+		})
+
+		It("should use the order of theirs if only theirs changed the order", func() {
+			result := merge(`[{name: a}, {name: b}, {name: c}]`, `[{name: a}, {name: b, x: 1}, {name: c}]`, `[{name: c}, {name: a}, {name: b}]`)
+			Expect(result.Conflicts()).To(BeEmpty())
+			Expect(output(result)).To(Equal("[{name: c}, {name: a}, {name: b, x: 1}]\n"))
+		})
+
+		It("should report a conflict if an entry is removed by one side and modified by the other", func() {
+			result := merge(`[{name: a, v: 1}, {name: b, v: 1}]`, `[{name: a, v: 1}]`, `[{name: a, v: 1}, {name: b, v: 2}]`)
+			Expect(result.Conflicts()).To(HaveLen(1))
+			Expect(result.Conflicts()[0].Path.String()).To(Equal("/name=b"))
+		})
+
+		It("should merge simple lists by position", func() {
+			result := merge(`{list: [a, b, c]}`, `{list: [a, b, c, d]}`, `{list: [z, a, c]}`)
+			Expect(result.Conflicts()).To(BeEmpty())
+			Expect(output(result)).To(Equal("{list: [z, a, c, d]}\n"))
+		})
+
+		It("should report a conflict if both sides change the same part of a simple list", func() {
+			result := merge(`{list: [a, b]}`, `{list: [a, b, c]}`, `{list: [a, b, d]}`)
+			Expect(output(result)).To(Equal("{list: [a, b, c]}\n"))
+			Expect(result.Conflicts()).To(HaveLen(1))
+			Expect(result.Conflicts()[0].Path.String()).To(Equal("/list/2"))
+		})
+	})
+
+	Context("merging documents", func() {
+		It("should merge Kubernetes resources by their names", func() {
+			result := merge(`---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: one
+data:
+  key: value
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: two
+data:
+  key: value
+`, `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: two
+data:
+  key: value
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: one
+data:
+  key: changed
+`, `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: one
+data:
+  key: value
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: three
+data:
+  key: value
+`)
+
+			Expect(result.Conflicts()).To(BeEmpty())
+			Expect(output(result)).To(Equal(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: one
+data:
+  key: changed
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: three
+data:
+  key: value
+`))
+		})
+	})
+
+	Context("marking conflicts", func() {
+		mergeWithConflictMarkers := func(base, ours, theirs string) dyff.MergeResult {
+			result, err := dyff.Merge3WithConflictMarkers(input("base", base), input("ours", ours), input("theirs", theirs))
+			Expect(err).ToNot(HaveOccurred())
+			return result
+		}
+
+		It("should surround the value of ours with conflict markers in comments", func() {
+			result := mergeWithConflictMarkers(`{a: {b: 1, c: 1}, d: 1}`, `{a: {b: 2, c: 1}}`, `{a: {b: 3, c: 1}, d: 2}`)
+			Expect(result.Conflicts()).To(HaveLen(2))
+			Expect(output(result)).To(Equal(`a:
+  # <<<<<<< ours
+  b: 2
+  # =======
+  # b: 3
+  # >>>>>>> theirs
+
+  c: 1
+# <<<<<<< ours
+# =======
+# d: 2
+# >>>>>>> theirs
+`))
+		})
+
+		It("should mark conflicting list entries", func() {
+			result := mergeWithConflictMarkers(`{list: [a, b]}`, `{list: [a, b, c]}`, `{list: [a, b, d]}`)
+			Expect(output(result)).To(Equal(`list:
+  - a
+  - b
+  # <<<<<<< ours
+  - c
+  # =======
+  # - d
+  # >>>>>>> theirs
+`))
+
+			result = mergeWithConflictMarkers(`[{name: a}, {name: b, v: 1}, {name: c}]`, `[{name: a}, {name: c}]`, `[{name: a}, {name: b, v: 2}, {name: c}]`)
+			Expect(output(result)).To(Equal(`- {name: a}
+# <<<<<<< ours
+# =======
+# - {name: b, v: 2}
+# >>>>>>> theirs
+- {name: c}
+`))
+		})
+
+		It("should mark documents that were removed by ours and modified by theirs", func() {
+			result := mergeWithConflictMarkers("---\n{name: a}\n---\n{name: b}\n", "---\n{name: a}\n", "---\n{name: a}\n---\n{name: b, v: 1}\n")
+			Expect(output(result)).To(Equal(`{name: a}
+
+# <<<<<<< ours
+# =======
+# {name: b, v: 1}
+# >>>>>>> theirs
+`))
+		})
+	})
+
+	Context("reporting conflicts", func() {
+		BeforeEach(func() {
+			SetColorSettings(OFF, OFF)
+		})
+
+		AfterEach(func() {
+			SetColorSettings(AUTO, AUTO)
+		})
+
+		It("should show both sides of each conflict", func() {
+			result := merge(`{a: {b: 1}, c: 1}`, `{a: {b: 2}}`, `{a: {b: 3}, c: 2}`)
+
+			var buf bytes.Buffer
+			report := dyff.ConflictReport{MergeResult: result, Indent: 2, OmitHeader: true}
+			Expect(report.WriteReport(&buf)).To(Succeed())
+			Expect(buf.String()).To(Equal(`
+a.b
+  ± conflicting changes
+    ours   theirs
+    2      3
+
+c
+  ± conflicting changes
+    ours        theirs
+    (removed)   2
+
+`))
+		})
+	})
+})
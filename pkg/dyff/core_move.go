@@ -0,0 +1,232 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// moveSimilarityThreshold is the minimum similarity of a removed and an added
+// subtree to consider them to be the same subtree at a different location
+const moveSimilarityThreshold = 0.5
+
+// moveCandidate is a map entry with a complex value that was removed or added,
+// it refers to the diff, detail, and position of the entry in the detail
+type moveCandidate struct {
+	diff   int
+	detail int
+	entry  int
+	path   ytbx.Path
+	value  *yamlv3.Node
+}
+
+// moves post-processes the differences to find subtrees (map entries with a
+// map or list value) that were removed at one path and added at a different
+// path of the same document. These are reported as moves at the new path,
+// followed by the differences in case the content was changed, too. Entries
+// of lists are not considered, since their path does not reliably point to
+// the same entry once other entries of the list were removed or added.
+func (compare *compare) moves(diffs []Diff) ([]Diff, error) {
+	if !compare.settings.DetectMapEntryMoves {
+		return diffs, nil
+	}
+
+	removals := collectMoveCandidates(diffs, REMOVAL)
+	additions := collectMoveCandidates(diffs, ADDITION)
+	if len(removals) == 0 || len(additions) == 0 {
+		return diffs, nil
+	}
+
+	// content of embedded documents is not moved, since it cannot be moved in
+	// and out of the string that contains the document
+	movable := func(removal, addition moveCandidate) bool {
+		return removal.path.DocumentIdx == addition.path.DocumentIdx &&
+			diffs[removal.diff].embedding() == nil && diffs[addition.diff].embedding() == nil &&
+			diffs[removal.diff].Path.ToGoPatchStyle() != diffs[addition.diff].Path.ToGoPatchStyle()
+	}
+
+	var pairs []renamePair
+	pairedFrom, pairedTo := map[int]struct{}{}, map[int]struct{}{}
+	addPair := func(from, to int) {
+		pairedFrom[from], pairedTo[to] = struct{}{}, struct{}{}
+		pairs = append(pairs, renamePair{from, to})
+	}
+
+	// subtrees with identical content first, then subtrees with similar content
+	for i, removal := range removals {
+		if err := compare.ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		hash := compare.calcNodeHash(removal.path, removal.value)
+		for j, addition := range additions {
+			if _, done := pairedTo[j]; done || !movable(removal, addition) {
+				continue
+			}
+
+			if hash == compare.calcNodeHash(addition.path, addition.value) {
+				addPair(i, j)
+				break
+			}
+		}
+	}
+
+	var fromValues, toValues []*yamlv3.Node
+	for i, removal := range removals {
+		fromValues = append(fromValues, removal.value)
+		if _, done := pairedFrom[i]; done {
+			fromValues[i] = &yamlv3.Node{Kind: yamlv3.ScalarNode}
+		}
+	}
+
+	for j, addition := range additions {
+		toValues = append(toValues, addition.value)
+		if _, done := pairedTo[j]; done {
+			toValues[j] = &yamlv3.Node{Kind: yamlv3.ScalarNode}
+		}
+	}
+
+	similar, err := findSimilarEntries(compare.ctx, fromValues, toValues, nil, moveSimilarityThreshold)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, pair := range similar {
+		if movable(removals[pair.from], additions[pair.to]) {
+			addPair(pair.from, pair.to)
+		}
+	}
+
+	if len(pairs) == 0 {
+		return diffs, nil
+	}
+
+	// the moves are reported right after the diff that contained the addition
+	movesByDiff := map[int][]Diff{}
+	moved := map[[3]int]struct{}{}
+	for _, pair := range pairs {
+		removal, addition := removals[pair.from], additions[pair.to]
+		moved[[3]int{removal.diff, removal.detail, removal.entry}] = struct{}{}
+		moved[[3]int{addition.diff, addition.detail, addition.entry}] = struct{}{}
+
+		source, target := removal.path, addition.path
+		movesByDiff[addition.diff] = append(movesByDiff[addition.diff], Diff{
+			Path: &target,
+			Details: []Detail{{
+				Kind:   MOVE,
+				From:   removal.value,
+				To:     addition.value,
+				Source: &source,
+			}},
+		})
+
+		changes, err := compare.objects(target, removal.value, addition.value)
+		if err != nil {
+			return nil, err
+		}
+
+		movesByDiff[addition.diff] = append(movesByDiff[addition.diff], changes...)
+	}
+
+	result := make([]Diff, 0, len(diffs))
+	for i, diff := range diffs {
+		details := make([]Detail, 0, len(diff.Details))
+		for j, detail := range diff.Details {
+			switch {
+			case detail.Kind == REMOVAL && detail.From.Kind == yamlv3.MappingNode:
+				if detail.From = withoutMovedEntries(detail.From, i, j, moved); len(detail.From.Content) == 0 {
+					continue
+				}
+
+			case detail.Kind == ADDITION && detail.To.Kind == yamlv3.MappingNode:
+				if detail.To = withoutMovedEntries(detail.To, i, j, moved); len(detail.To.Content) == 0 {
+					continue
+				}
+			}
+
+			details = append(details, detail)
+		}
+
+		if len(details) > 0 {
+			result = append(result, Diff{Path: diff.Path, Details: details})
+		}
+
+		result = append(result, movesByDiff[i]...)
+	}
+
+	return result, nil
+}
+
+// collectMoveCandidates returns all map entries with a map or list value that
+// are part of the removals or additions (depending on the kind) of the diffs
+func collectMoveCandidates(diffs []Diff, kind rune) []moveCandidate {
+	var result []moveCandidate
+	for i, diff := range diffs {
+		if diff.Path == nil {
+			continue
+		}
+
+		for j, detail := range diff.Details {
+			if detail.Kind != kind {
+				continue
+			}
+
+			node := detail.To
+			if kind == REMOVAL {
+				node = detail.From
+			}
+
+			if node == nil || node.Kind != yamlv3.MappingNode {
+				continue
+			}
+
+			for k := 0; k < len(node.Content); k += 2 {
+				switch value := followAlias(node.Content[k+1]); value.Kind {
+				case yamlv3.MappingNode, yamlv3.SequenceNode:
+					result = append(result, moveCandidate{
+						diff:   i,
+						detail: j,
+						entry:  k,
+						path:   ytbx.NewPathWithNamedElement(*diff.Path, node.Content[k].Value),
+						value:  value,
+					})
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// withoutMovedEntries returns a copy of the mapping node without the entries
+// that were moved, the original node stays untouched
+func withoutMovedEntries(node *yamlv3.Node, diff, detail int, moved map[[3]int]struct{}) *yamlv3.Node {
+	result := *node
+	result.Content = make([]*yamlv3.Node, 0, len(node.Content))
+	for k := 0; k < len(node.Content); k += 2 {
+		if _, ok := moved[[3]int{diff, detail, k}]; !ok {
+			result.Content = append(result.Content, node.Content[k], node.Content[k+1])
+		}
+	}
+
+	return &result
+}
@@ -0,0 +1,699 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// JSONPatchOperation is an operation of a JSON Patch (RFC 6902)
+type JSONPatchOperation struct {
+	Op    string       `yaml:"op"`
+	Path  string       `yaml:"path"`
+	From  string       `yaml:"from,omitempty"`
+	Value *yamlv3.Node `yaml:"value,omitempty"`
+}
+
+// MarshalJSON writes the operation with the value as JSON, the order of the
+// keys of maps in the value is preserved
+func (operation JSONPatchOperation) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `{"op":%s,"path":%s`, quoteJSON(operation.Op), quoteJSON(operation.Path))
+
+	if operation.From != "" || operation.Op == "move" || operation.Op == "copy" {
+		fmt.Fprintf(&buf, `,"from":%s`, quoteJSON(operation.From))
+	}
+
+	if operation.Value != nil {
+		buf.WriteString(`,"value":`)
+		if err := writeJSON(&buf, operation.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+// UnmarshalYAML reads the operation from a JSON or YAML map, the value is kept
+// as a node
+func (operation *JSONPatchOperation) UnmarshalYAML(node *yamlv3.Node) error {
+	if node.Kind != yamlv3.MappingNode {
+		return fmt.Errorf("failed to parse JSON patch operation, expected a map")
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		switch key {
+		case "op":
+			operation.Op = value.Value
+
+		case "path":
+			operation.Path = value.Value
+
+		case "from":
+			operation.From = value.Value
+
+		case "value":
+			operation.Value = value
+		}
+	}
+
+	return nil
+}
+
+// ApplyJSONPatch applies the JSON Patch (RFC 6902) to a copy of the document
+// and returns the patched copy, the document itself stays untouched. The
+// document can be a YAML document node or any other node. Key order, comments,
+// and anchors of the document are preserved.
+func ApplyJSONPatch(document *yamlv3.Node, patch []byte) (*yamlv3.Node, error) {
+	var operations []JSONPatchOperation
+	if err := yamlv3.Unmarshal(patch, &operations); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON patch: %w", err)
+	}
+
+	root := cloneNode(document, map[*yamlv3.Node]*yamlv3.Node{})
+	content := &root
+	if root.Kind == yamlv3.DocumentNode && len(root.Content) == 1 {
+		content = &root.Content[0]
+	}
+
+	for _, operation := range operations {
+		blockStyle(operation.Value)
+		if err := applyOperation(content, operation); err != nil {
+			return nil, err
+		}
+	}
+
+	repairAliases(root)
+	return root, nil
+}
+
+// ApplyMergePatch applies the JSON Merge Patch (RFC 7386) to a copy of the
+// document and returns the patched copy, the document itself stays untouched
+func ApplyMergePatch(document *yamlv3.Node, patch []byte) (*yamlv3.Node, error) {
+	var node yamlv3.Node
+	if err := yamlv3.Unmarshal(patch, &node); err != nil {
+		return nil, fmt.Errorf("failed to parse merge patch: %w", err)
+	}
+
+	root := cloneNode(document, map[*yamlv3.Node]*yamlv3.Node{})
+	if len(node.Content) == 0 {
+		return root, nil
+	}
+
+	blockStyle(&node)
+
+	if root.Kind == yamlv3.DocumentNode && len(root.Content) == 1 {
+		root.Content[0] = applyMergePatch(root.Content[0], node.Content[0])
+	} else {
+		root = applyMergePatch(root, node.Content[0])
+	}
+
+	repairAliases(root)
+	return root, nil
+}
+
+func applyMergePatch(target *yamlv3.Node, patch *yamlv3.Node) *yamlv3.Node {
+	patch = followAlias(patch)
+	if patch.Kind != yamlv3.MappingNode {
+		return copyNode(patch, nil)
+	}
+
+	// changes must not affect the content an alias refers to
+	if target != nil && target.Kind == yamlv3.AliasNode {
+		target = copyNode(target, nil)
+	}
+
+	if target == nil || target.Kind != yamlv3.MappingNode {
+		target = &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+	}
+
+	for i := 0; i+1 < len(patch.Content); i += 2 {
+		key, value := patch.Content[i].Value, followAlias(patch.Content[i+1])
+		idx := mappingKeyIndex(target, key)
+
+		switch {
+		case value.Kind == yamlv3.ScalarNode && value.Tag == "!!null":
+			if idx >= 0 {
+				target.Content = append(target.Content[:idx], target.Content[idx+2:]...)
+			}
+
+		case idx >= 0:
+			result := applyMergePatch(target.Content[idx+1], value)
+			keepComments(target.Content[idx+1], result)
+			target.Content[idx+1] = result
+
+		default:
+			target.Content = append(target.Content,
+				&yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: key},
+				applyMergePatch(nil, value),
+			)
+		}
+	}
+
+	return target
+}
+
+func applyOperation(root **yamlv3.Node, operation JSONPatchOperation) error {
+	path, err := parsePointer(operation.Path)
+	if err != nil {
+		return err
+	}
+
+	switch operation.Op {
+	case "add":
+		if operation.Value == nil {
+			return fmt.Errorf("failed to apply add operation at %q: no value", operation.Path)
+		}
+
+		return addNode(root, path, copyNode(operation.Value, nil))
+
+	case "remove":
+		_, err := removeNode(root, path)
+		return err
+
+	case "replace":
+		if operation.Value == nil {
+			return fmt.Errorf("failed to apply replace operation at %q: no value", operation.Path)
+		}
+
+		return replaceNode(root, path, copyNode(operation.Value, nil))
+
+	case "move", "copy":
+		from, err := parsePointer(operation.From)
+		if err != nil {
+			return err
+		}
+
+		if operation.Op == "move" && strings.HasPrefix(operation.Path+"/", operation.From+"/") {
+			if operation.Path == operation.From {
+				return nil
+			}
+
+			return fmt.Errorf("failed to move %q into one of its children %q", operation.From, operation.Path)
+		}
+
+		var value *yamlv3.Node
+		if operation.Op == "move" {
+			value, err = removeNode(root, from)
+		} else {
+			value, err = lookupNode(*root, from)
+			value = copyNode(value, nil)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		return addNode(root, path, value)
+
+	case "test":
+		value, err := lookupNode(*root, path)
+		if err != nil {
+			return err
+		}
+
+		if operation.Value == nil || !equalJSON(value, operation.Value) {
+			return fmt.Errorf("test operation failed, value at %q does not match", operation.Path)
+		}
+
+		return nil
+
+	default:
+		return fmt.Errorf("unsupported JSON patch operation %q", operation.Op)
+	}
+}
+
+// parsePointer parses a JSON Pointer (RFC 6901) into its reference tokens
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q, it has to start with a slash", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+
+	return tokens, nil
+}
+
+// pointer creates a JSON Pointer (RFC 6901) from the reference tokens
+func pointer(tokens []string) string {
+	var buf strings.Builder
+	for _, token := range tokens {
+		buf.WriteString("/")
+		buf.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(token))
+	}
+
+	return buf.String()
+}
+
+// lookupNode returns the node at the path, aliases are followed
+func lookupNode(node *yamlv3.Node, path []string) (*yamlv3.Node, error) {
+	result, err := lookupSlot(node, path, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return followAlias(result), nil
+}
+
+// writableNode returns the node at the path, aliases on the way are replaced
+// with a copy of the content they refer to, so that changes to the node do not
+// affect the anchored content
+func writableNode(node *yamlv3.Node, path []string) (*yamlv3.Node, error) {
+	return lookupSlot(node, path, true)
+}
+
+func lookupSlot(node *yamlv3.Node, path []string, writable bool) (*yamlv3.Node, error) {
+	if writable && node.Kind == yamlv3.AliasNode {
+		*node = *copyNode(node, nil)
+	}
+
+	for i, token := range path {
+		var slot **yamlv3.Node
+		switch parent := followAlias(node); parent.Kind {
+		case yamlv3.MappingNode:
+			idx := mappingKeyIndex(parent, token)
+			if idx < 0 {
+				return nil, fmt.Errorf("failed to find %q", pointer(path[:i+1]))
+			}
+
+			slot = &parent.Content[idx+1]
+
+		case yamlv3.SequenceNode:
+			idx, err := sequenceIndex(parent, token, false)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find %q: %w", pointer(path[:i+1]), err)
+			}
+
+			slot = &parent.Content[idx]
+
+		default:
+			return nil, fmt.Errorf("failed to find %q, %s is neither a map nor a list", pointer(path[:i+1]), pointer(path[:i]))
+		}
+
+		if writable && (*slot).Kind == yamlv3.AliasNode {
+			*slot = copyNode(*slot, nil)
+		}
+
+		node = *slot
+	}
+
+	return node, nil
+}
+
+func addNode(root **yamlv3.Node, path []string, value *yamlv3.Node) error {
+	if len(path) == 0 {
+		*root = value
+		return nil
+	}
+
+	parent, err := writableNode(*root, path[:len(path)-1])
+	if err != nil {
+		return err
+	}
+
+	token := path[len(path)-1]
+	switch parent.Kind {
+	case yamlv3.MappingNode:
+		if idx := mappingKeyIndex(parent, token); idx >= 0 {
+			parent.Content[idx+1] = value
+			return nil
+		}
+
+		parent.Content = append(parent.Content, &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: token}, value)
+		return nil
+
+	case yamlv3.SequenceNode:
+		idx, err := sequenceIndex(parent, token, true)
+		if err != nil {
+			return fmt.Errorf("failed to add %q: %w", pointer(path), err)
+		}
+
+		parent.Content = append(parent.Content, nil)
+		copy(parent.Content[idx+1:], parent.Content[idx:])
+		parent.Content[idx] = value
+		return nil
+
+	default:
+		return fmt.Errorf("failed to add %q, %s is neither a map nor a list", pointer(path), pointer(path[:len(path)-1]))
+	}
+}
+
+func removeNode(root **yamlv3.Node, path []string) (*yamlv3.Node, error) {
+	if len(path) == 0 {
+		value := *root
+		*root = &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!null", Value: "null"}
+		return value, nil
+	}
+
+	parent, err := writableNode(*root, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	token := path[len(path)-1]
+	switch parent.Kind {
+	case yamlv3.MappingNode:
+		idx := mappingKeyIndex(parent, token)
+		if idx < 0 {
+			return nil, fmt.Errorf("failed to remove %q, it does not exist", pointer(path))
+		}
+
+		value := parent.Content[idx+1]
+		parent.Content = append(parent.Content[:idx], parent.Content[idx+2:]...)
+		return value, nil
+
+	case yamlv3.SequenceNode:
+		idx, err := sequenceIndex(parent, token, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to remove %q: %w", pointer(path), err)
+		}
+
+		value := parent.Content[idx]
+		parent.Content = append(parent.Content[:idx], parent.Content[idx+1:]...)
+		return value, nil
+
+	default:
+		return nil, fmt.Errorf("failed to remove %q, %s is neither a map nor a list", pointer(path), pointer(path[:len(path)-1]))
+	}
+}
+
+// replaceNode replaces the existing node at the path, the position of the
+// node and its comments are kept unless the value has comments of its own
+func replaceNode(root **yamlv3.Node, path []string, value *yamlv3.Node) error {
+	if len(path) == 0 {
+		*root = value
+		return nil
+	}
+
+	parent, err := writableNode(*root, path[:len(path)-1])
+	if err != nil {
+		return err
+	}
+
+	var slot **yamlv3.Node
+	token := path[len(path)-1]
+	switch parent.Kind {
+	case yamlv3.MappingNode:
+		idx := mappingKeyIndex(parent, token)
+		if idx < 0 {
+			return fmt.Errorf("failed to replace %q, it does not exist", pointer(path))
+		}
+
+		slot = &parent.Content[idx+1]
+
+	case yamlv3.SequenceNode:
+		idx, err := sequenceIndex(parent, token, false)
+		if err != nil {
+			return fmt.Errorf("failed to replace %q: %w", pointer(path), err)
+		}
+
+		slot = &parent.Content[idx]
+
+	default:
+		return fmt.Errorf("failed to replace %q, %s is neither a map nor a list", pointer(path), pointer(path[:len(path)-1]))
+	}
+
+	keepComments(*slot, value)
+	*slot = value
+	return nil
+}
+
+// keepComments moves the comments of the old node to the new node, in case
+// the new node has no comments of its own
+func keepComments(old *yamlv3.Node, value *yamlv3.Node) {
+	if value.HeadComment == "" && value.LineComment == "" && value.FootComment == "" {
+		value.HeadComment, value.LineComment, value.FootComment = old.HeadComment, old.LineComment, old.FootComment
+	}
+}
+
+// blockStyle removes the flow and quoted styles of the node and its children,
+// which a patch in JSON format has, so that the values of the patch fit into a
+// YAML document. Strings that would not keep their value without the quotes,
+// for example a literal block cannot start with a newline, stay quoted.
+func blockStyle(node *yamlv3.Node) {
+	if node == nil {
+		return
+	}
+
+	style := node.Style
+	node.Style &^= yamlv3.FlowStyle | yamlv3.DoubleQuotedStyle
+	if node.Kind == yamlv3.ScalarNode && style&yamlv3.DoubleQuotedStyle != 0 && !keepsValue(node) {
+		node.Style = style
+	}
+
+	for _, child := range node.Content {
+		blockStyle(child)
+	}
+}
+
+// keepsValue checks whether the scalar node has the same value after it was
+// written with its style and read again
+func keepsValue(node *yamlv3.Node) bool {
+	data, err := yamlv3.Marshal(&yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: node.Tag, Value: node.Value, Style: node.Style})
+	if err != nil {
+		return false
+	}
+
+	var result yamlv3.Node
+	if err := yamlv3.Unmarshal(data, &result); err != nil || len(result.Content) != 1 {
+		return false
+	}
+
+	return result.Content[0].Value == node.Value
+}
+
+// cloneNode creates a deep copy of the node, which keeps anchors and aliases,
+// the clones map is used to let aliases refer to the copied anchors
+func cloneNode(node *yamlv3.Node, clones map[*yamlv3.Node]*yamlv3.Node) *yamlv3.Node {
+	if node == nil {
+		return nil
+	}
+
+	if result, ok := clones[node]; ok {
+		return result
+	}
+
+	result := &yamlv3.Node{}
+	clones[node] = result
+
+	*result = *node
+	result.Alias = cloneNode(node.Alias, clones)
+	result.Content = make([]*yamlv3.Node, len(node.Content))
+	for i, child := range node.Content {
+		result.Content[i] = cloneNode(child, clones)
+	}
+
+	return result
+}
+
+// repairAliases replaces aliases, which refer to anchors that are no longer
+// part of the document (or come after the alias), with a copy of the content
+func repairAliases(root *yamlv3.Node) {
+	anchors := map[*yamlv3.Node]struct{}{}
+
+	var walk func(node *yamlv3.Node)
+	walk = func(node *yamlv3.Node) {
+		if node == nil {
+			return
+		}
+
+		if node.Kind == yamlv3.AliasNode {
+			if _, ok := anchors[node.Alias]; !ok {
+				*node = *copyNode(node, nil)
+			}
+
+			return
+		}
+
+		if node.Anchor != "" {
+			anchors[node] = struct{}{}
+		}
+
+		for _, child := range node.Content {
+			walk(child)
+		}
+	}
+
+	walk(root)
+}
+
+func mappingKeyIndex(node *yamlv3.Node, key string) int {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if followAlias(node.Content[i]).Value == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// sequenceIndex returns the index the token refers to, the end of the list
+// (`-` or the length of the list) is only valid for additions
+func sequenceIndex(node *yamlv3.Node, token string, addition bool) (int, error) {
+	if token == "-" && addition {
+		return len(node.Content), nil
+	}
+
+	idx, err := strconv.Atoi(token)
+	if err != nil || idx < 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid list index %q", token)
+	}
+
+	if idx > len(node.Content) || (idx == len(node.Content) && !addition) {
+		return 0, fmt.Errorf("list index %d is out of range", idx)
+	}
+
+	return idx, nil
+}
+
+// copyNode creates a deep copy of the node where aliases are replaced with a
+// copy of the content they refer to, the optional copies map is filled with
+// the copies of each node
+func copyNode(node *yamlv3.Node, copies map[*yamlv3.Node]*yamlv3.Node) *yamlv3.Node {
+	if node == nil {
+		return nil
+	}
+
+	result := *followAlias(node)
+	result.Anchor, result.Alias = "", nil
+	result.Content = make([]*yamlv3.Node, len(result.Content))
+	for i, child := range followAlias(node).Content {
+		result.Content[i] = copyNode(child, copies)
+	}
+
+	if copies != nil {
+		copies[node] = &result
+	}
+
+	return &result
+}
+
+// writeJSON writes the node as JSON, the order of map keys is preserved
+func writeJSON(buf *bytes.Buffer, node *yamlv3.Node) error {
+	switch node = followAlias(node); node.Kind {
+	case yamlv3.DocumentNode:
+		if len(node.Content) == 0 {
+			buf.WriteString("null")
+			return nil
+		}
+
+		return writeJSON(buf, node.Content[0])
+
+	case yamlv3.MappingNode:
+		buf.WriteString("{")
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+
+			buf.WriteString(quoteJSON(followAlias(node.Content[i]).Value))
+			buf.WriteString(":")
+			if err := writeJSON(buf, node.Content[i+1]); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteString("}")
+		return nil
+
+	case yamlv3.SequenceNode:
+		buf.WriteString("[")
+		for i, entry := range node.Content {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+
+			if err := writeJSON(buf, entry); err != nil {
+				return err
+			}
+		}
+
+		buf.WriteString("]")
+		return nil
+
+	default:
+		var value interface{}
+		if err := node.Decode(&value); err != nil {
+			return err
+		}
+
+		// values that have no JSON representation are written as strings
+		switch typed := value.(type) {
+		case float64:
+			if math.IsInf(typed, 0) || math.IsNaN(typed) {
+				value = node.Value
+			}
+
+		case bool, int, int64, uint64, string, nil:
+
+		default:
+			value = node.Value
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+
+		buf.Write(data)
+		return nil
+	}
+}
+
+func quoteJSON(value string) string {
+	data, _ := json.Marshal(value)
+	return string(data)
+}
+
+// equalJSON returns whether both nodes have the same JSON representation,
+// regardless of the order of map keys
+func equalJSON(a, b *yamlv3.Node) bool {
+	decode := func(node *yamlv3.Node) (interface{}, bool) {
+		var buf bytes.Buffer
+		if err := writeJSON(&buf, node); err != nil {
+			return nil, false
+		}
+
+		var result interface{}
+		return result, json.Unmarshal(buf.Bytes(), &result) == nil
+	}
+
+	valueA, okA := decode(a)
+	valueB, okB := decode(b)
+	return okA && okB && reflect.DeepEqual(valueA, valueB)
+}
@@ -0,0 +1,93 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gonvenience/ytbx"
+)
+
+// pathPattern is a path with wildcards to match paths of the compared
+// documents. It can be written in Dot-Style or GoPatch style, where `*` matches
+// exactly one path element and `**` matches any number of path elements, e.g.
+// `spec.template.spec.containers.*.ports` or `/**/containers/*/ports`.
+type pathPattern struct {
+	segments []string
+}
+
+func newPathPattern(pattern string) pathPattern {
+	var segments []string
+	if strings.HasPrefix(pattern, "/") {
+		segments = strings.Split(strings.Trim(pattern, "/"), "/")
+	} else {
+		segments = strings.Split(pattern, ".")
+	}
+
+	if len(segments) == 1 && segments[0] == "" {
+		segments = nil
+	}
+
+	return pathPattern{segments: segments}
+}
+
+func (pattern pathPattern) matches(path ytbx.Path) bool {
+	return matchSegments(pattern.segments, path.PathElements)
+}
+
+func matchSegments(segments []string, elements []ytbx.PathElement) bool {
+	if len(segments) == 0 {
+		return len(elements) == 0
+	}
+
+	if segments[0] == "**" {
+		for i := 0; i <= len(elements); i++ {
+			if matchSegments(segments[1:], elements[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(elements) == 0 || !matchSegment(segments[0], elements[0]) {
+		return false
+	}
+
+	return matchSegments(segments[1:], elements[1:])
+}
+
+// matchSegment checks whether a pattern segment matches the path element, a
+// named list entry can be matched by its name, or by its key and name
+func matchSegment(segment string, element ytbx.PathElement) bool {
+	switch {
+	case segment == "*":
+		return true
+
+	case element.Name != "":
+		return segment == element.Name ||
+			(element.Key != "" && segment == element.Key+"="+element.Name)
+
+	default:
+		return segment == strconv.Itoa(element.Idx)
+	}
+}
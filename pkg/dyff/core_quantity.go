@@ -0,0 +1,255 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"math/big"
+	"regexp"
+
+	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+type quantityFormat int
+
+const (
+	decimalSI quantityFormat = iota
+	binarySI
+)
+
+type quantitySuffix struct {
+	suffix string
+	base   int64
+	exp    int64
+}
+
+// suffixes in the order they are tried when a quantity is formatted, the
+// largest suffix resulting in an integer value wins
+var (
+	binarySuffixes = []quantitySuffix{
+		{"Ei", 2, 60},
+		{"Pi", 2, 50},
+		{"Ti", 2, 40},
+		{"Gi", 2, 30},
+		{"Mi", 2, 20},
+		{"Ki", 2, 10},
+	}
+
+	decimalSuffixes = []quantitySuffix{
+		{"E", 10, 18},
+		{"P", 10, 15},
+		{"T", 10, 12},
+		{"G", 10, 9},
+		{"M", 10, 6},
+		{"k", 10, 3},
+		{"", 10, 0},
+		{"m", 10, -3},
+		{"u", 10, -6},
+		{"n", 10, -9},
+	}
+)
+
+var quantityRegEx = regexp.MustCompile(`^([+-]?(?:[0-9]+(?:\.[0-9]*)?|\.[0-9]+))(?:(Ki|Mi|Gi|Ti|Pi|Ei|[numkMGTPE])|[eE]([+-]?[0-9]+))?$`)
+
+// quantityParentNames are the names of the maps in Kubernetes resources that
+// contain resource quantities, e.g. `resources.requests` in a container spec
+var quantityParentNames = map[string]struct{}{
+	"requests":    {},
+	"limits":      {},
+	"hard":        {},
+	"used":        {},
+	"capacity":    {},
+	"allocatable": {},
+}
+
+// quantities compares two scalars as Kubernetes resource quantities, in case
+// the path refers to a quantity field of a Kubernetes resource and both values
+// can be parsed
+func (compare *compare) quantities(path ytbx.Path, from *yamlv3.Node, to *yamlv3.Node) ([]Diff, bool) {
+	if from.Kind != yamlv3.ScalarNode || to.Kind != yamlv3.ScalarNode || !isQuantityPath(path) || !isKubernetesResource(path) {
+		return nil, false
+	}
+
+	fromValue, fromFormat, ok := parseQuantity(from.Value)
+	if !ok {
+		return nil, false
+	}
+
+	toValue, toFormat, ok := parseQuantity(to.Value)
+	if !ok {
+		return nil, false
+	}
+
+	if fromValue.Cmp(toValue) == 0 {
+		return nil, true
+	}
+
+	format := decimalSI
+	if fromFormat == binarySI || toFormat == binarySI {
+		format = binarySI
+	}
+
+	delta := new(big.Rat).Sub(toValue, fromValue)
+	return []Diff{{
+		&path,
+		[]Detail{{
+			Kind: MODIFICATION,
+			From: from,
+			To:   to,
+			Note: formatQuantityDelta(delta, format),
+		}},
+	}}, true
+}
+
+func isQuantityPath(path ytbx.Path) bool {
+	elements := path.PathElements
+	if len(elements) == 0 {
+		return false
+	}
+
+	last := elements[len(elements)-1]
+	if last.Key != "" || last.Name == "" {
+		return false
+	}
+
+	if last.Name == "sizeLimit" {
+		return true
+	}
+
+	if len(elements) < 2 {
+		return false
+	}
+
+	parent := elements[len(elements)-2]
+	if parent.Key != "" {
+		return false
+	}
+
+	_, ok := quantityParentNames[parent.Name]
+	return ok
+}
+
+// isKubernetesResource returns whether the document the path belongs to is a
+// Kubernetes resource, which requires an `apiVersion` and a `kind`
+func isKubernetesResource(path ytbx.Path) bool {
+	if path.Root == nil || path.DocumentIdx < 0 || path.DocumentIdx >= len(path.Root.Documents) {
+		return false
+	}
+
+	document := path.Root.Documents[path.DocumentIdx]
+	if document.Kind == yamlv3.DocumentNode && len(document.Content) > 0 {
+		document = document.Content[0]
+	}
+
+	_, hasAPIVersion := findValueByKey(document, "apiVersion")
+	_, hasKind := findValueByKey(document, "kind")
+	return hasAPIVersion && hasKind
+}
+
+// parseQuantity parses the Kubernetes resource quantity notation, which is a
+// number with an optional binary (Ki, Mi, ...), decimal (m, k, M, ...), or
+// exponent (e3, E-2, ...) suffix
+func parseQuantity(value string) (*big.Rat, quantityFormat, bool) {
+	matches := quantityRegEx.FindStringSubmatch(value)
+	if matches == nil {
+		return nil, decimalSI, false
+	}
+
+	number, ok := new(big.Rat).SetString(matches[1])
+	if !ok {
+		return nil, decimalSI, false
+	}
+
+	switch {
+	case matches[2] != "":
+		for _, suffixes := range [][]quantitySuffix{binarySuffixes, decimalSuffixes} {
+			for _, suffix := range suffixes {
+				if suffix.suffix == matches[2] {
+					format := decimalSI
+					if suffix.base == 2 {
+						format = binarySI
+					}
+
+					return number.Mul(number, suffix.factor()), format, true
+				}
+			}
+		}
+
+		return nil, decimalSI, false
+
+	case matches[3] != "":
+		exp, ok := new(big.Int).SetString(matches[3], 10)
+		// larger exponents are not used for resources in practice
+		if !ok || exp.CmpAbs(big.NewInt(64)) > 0 {
+			return nil, decimalSI, false
+		}
+
+		factor := quantitySuffix{base: 10, exp: exp.Int64()}.factor()
+		return number.Mul(number, factor), decimalSI, true
+	}
+
+	return number, decimalSI, true
+}
+
+func formatQuantityDelta(delta *big.Rat, format quantityFormat) string {
+	sign := "+"
+	if delta.Sign() < 0 {
+		sign = "-"
+	}
+
+	return sign + formatQuantity(new(big.Rat).Abs(delta), format)
+}
+
+// formatQuantity returns the quantity using the largest suffix that results in
+// an integer number, binary suffixes are only used for integer values
+func formatQuantity(value *big.Rat, format quantityFormat) string {
+	if format == binarySI && value.IsInt() {
+		for _, suffix := range binarySuffixes {
+			if scaled := new(big.Rat).Quo(value, suffix.factor()); scaled.IsInt() {
+				return scaled.Num().String() + suffix.suffix
+			}
+		}
+
+		return value.Num().String()
+	}
+
+	for _, suffix := range decimalSuffixes {
+		if scaled := new(big.Rat).Quo(value, suffix.factor()); scaled.IsInt() {
+			return scaled.Num().String() + suffix.suffix
+		}
+	}
+
+	return value.FloatString(9)
+}
+
+func (suffix quantitySuffix) factor() *big.Rat {
+	exp := suffix.exp
+	if exp < 0 {
+		exp = -exp
+	}
+
+	power := new(big.Int).Exp(big.NewInt(suffix.base), big.NewInt(exp), nil)
+	if suffix.exp < 0 {
+		return new(big.Rat).SetFrac(big.NewInt(1), power)
+	}
+
+	return new(big.Rat).SetInt(power)
+}
@@ -0,0 +1,413 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// renameSimilarityThreshold is the minimum similarity of a removed and an
+// added entry to consider them to be the same entry with a new name
+const renameSimilarityThreshold = 0.5
+
+// renameMinimumLeaves is the minimum number of values an entry needs to have
+// (including its identifier), entries with less content are too small to tell
+// a rename from a replacement
+const renameMinimumLeaves = 2
+
+type renamePair struct {
+	from int
+	to   int
+}
+
+// findRenames pairs removed and added entries (maps or lists) based on the
+// similarity of their content, where the excluded fields (e.g. the identifier
+// of a list entry) are not taken into account
+func findRenames(ctx context.Context, removals, additions []*yamlv3.Node, excludedFields []string) ([]renamePair, error) {
+	return findSimilarEntries(ctx, removals, additions, excludedFields, renameSimilarityThreshold)
+}
+
+// findSimilarEntries pairs removed and added entries (maps or lists) with a
+// similarity of at least the given threshold, the most similar pairs first. It
+// stops once the context is done, since every entry is compared with every other.
+func findSimilarEntries(ctx context.Context, removals, additions []*yamlv3.Node, excludedFields []string, threshold float64) ([]renamePair, error) {
+	type candidate struct {
+		renamePair
+		score float64
+	}
+
+	collect := func(nodes []*yamlv3.Node) []map[string]int {
+		result := make([]map[string]int, len(nodes))
+		for i, node := range nodes {
+			switch node = followAlias(node); node.Kind {
+			case yamlv3.MappingNode, yamlv3.SequenceNode:
+				// the excluded fields (e.g. the identifier) count towards the size
+				// of the entry, but not towards the similarity of the entries
+				leaves := map[string]int{}
+				collectLeaves(node, "", leaves, true)
+				if len(leaves) < renameMinimumLeaves {
+					continue
+				}
+
+				if len(excludedFields) > 0 {
+					leaves = map[string]int{}
+					collectLeaves(withoutFields(node, excludedFields), "", leaves, true)
+
+					// an entry with only one value next to its identifier, e.g. a
+					// container with a name and an image, is also compared by the
+					// location of the value, since the value alone can change
+					if len(leaves) == 1 {
+						collectLeaves(withoutFields(node, excludedFields), "", leaves, false)
+					}
+				}
+
+				result[i] = leaves
+			}
+		}
+
+		return result
+	}
+
+	fromLeaves, toLeaves := collect(removals), collect(additions)
+
+	var candidates []candidate
+	for i := range fromLeaves {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		for j := range toLeaves {
+			if fromLeaves[i] == nil || toLeaves[j] == nil {
+				continue
+			}
+
+			if score := similarity(fromLeaves[i], toLeaves[j]); score >= threshold {
+				candidates = append(candidates, candidate{renamePair{i, j}, score})
+			}
+		}
+	}
+
+	// the most similar entries are paired first, every entry only once
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+
+	var result []renamePair
+	pairedFrom, pairedTo := map[int]struct{}{}, map[int]struct{}{}
+	for _, candidate := range candidates {
+		_, fromDone := pairedFrom[candidate.from]
+		_, toDone := pairedTo[candidate.to]
+		if fromDone || toDone {
+			continue
+		}
+
+		pairedFrom[candidate.from], pairedTo[candidate.to] = struct{}{}, struct{}{}
+		result = append(result, candidate.renamePair)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].from < result[j].from
+	})
+
+	return result, nil
+}
+
+// collectLeaves counts all scalar values in the node by their relative path,
+// or only the relative paths of the scalar values if the values are omitted
+func collectLeaves(node *yamlv3.Node, prefix string, result map[string]int, withValues bool) {
+	switch node = followAlias(node); node.Kind {
+	case yamlv3.MappingNode:
+		for i := 0; i < len(node.Content); i += 2 {
+			collectLeaves(node.Content[i+1], prefix+"/"+node.Content[i].Value, result, withValues)
+		}
+
+	case yamlv3.SequenceNode:
+		for i, entry := range node.Content {
+			collectLeaves(entry, prefix+"/"+strconv.Itoa(i), result, withValues)
+		}
+
+	default:
+		if !withValues {
+			result[prefix]++
+			return
+		}
+
+		result[fmt.Sprintf("%s=%s", prefix, node.Value)]++
+	}
+}
+
+// similarity calculates the Sørensen–Dice coefficient of both sets of leaves
+func similarity(a, b map[string]int) float64 {
+	var sizeA, sizeB, common int
+	for leaf, countA := range a {
+		sizeA += countA
+		common += min(countA, b[leaf])
+	}
+
+	for _, countB := range b {
+		sizeB += countB
+	}
+
+	if sizeA+sizeB == 0 {
+		return 0
+	}
+
+	return float64(2*common) / float64(sizeA+sizeB)
+}
+
+// withoutFields returns a copy of the mapping node without the given fields,
+// the original node stays untouched
+func withoutFields(node *yamlv3.Node, fields []string) *yamlv3.Node {
+	if node.Kind != yamlv3.MappingNode || len(fields) == 0 {
+		return node
+	}
+
+	excluded := map[string]struct{}{}
+	for _, field := range fields {
+		excluded[field] = struct{}{}
+	}
+
+	result := *node
+	result.Content = make([]*yamlv3.Node, 0, len(node.Content))
+	for i := 0; i < len(node.Content); i += 2 {
+		if _, ok := excluded[node.Content[i].Value]; !ok {
+			result.Content = append(result.Content, node.Content[i], node.Content[i+1])
+		}
+	}
+
+	return &result
+}
+
+// renamedListEntries compares the list entries that were renamed, which are
+// removed from the provided lists of removals and additions
+func (compare *compare) renamedListEntries(path ytbx.Path, identifier listItemIdentifier, removals, additions []*yamlv3.Node) ([]Diff, []*yamlv3.Node, []*yamlv3.Node, error) {
+	fields := identifierFields(identifier)
+	if !compare.settings.DetectEntryRenames || len(fields) == 0 || len(removals) == 0 || len(additions) == 0 {
+		return nil, removals, additions, nil
+	}
+
+	pairs, err := findRenames(compare.ctx, removals, additions, fields)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if len(pairs) == 0 {
+		return nil, removals, additions, nil
+	}
+
+	var result []Diff
+	for _, pair := range pairs {
+		fromName, err := identifier.Name(followAlias(removals[pair.from]))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		toName, err := identifier.Name(followAlias(additions[pair.to]))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		entryPath := newPathWithListItem(path, identifier, fromName)
+		result = append(result, Diff{
+			Path: &entryPath,
+			Details: []Detail{{
+				Kind: RENAME,
+				From: &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: fromName},
+				To:   &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: toName},
+			}},
+		})
+
+		diffs, err := compare.objects(
+			entryPath,
+			withoutFields(followAlias(removals[pair.from]), fields),
+			withoutFields(followAlias(additions[pair.to]), fields),
+		)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		result = append(result, compare.withAnchorChanges(entryPath, removals[pair.from], additions[pair.to], diffs)...)
+	}
+
+	remainingRemovals, remainingAdditions := remainingEntries(pairs, removals, additions, 1)
+	return result, remainingRemovals, remainingAdditions, nil
+}
+
+// renamedMappingKeys compares the values of keys that were renamed, which are
+// removed from the provided lists of removals and additions (key value pairs)
+func (compare *compare) renamedMappingKeys(path ytbx.Path, removals, additions []*yamlv3.Node) ([]Diff, []*yamlv3.Node, []*yamlv3.Node, error) {
+	if !compare.settings.DetectEntryRenames || len(removals) == 0 || len(additions) == 0 {
+		return nil, removals, additions, nil
+	}
+
+	values := func(keyValuePairs []*yamlv3.Node) []*yamlv3.Node {
+		result := make([]*yamlv3.Node, 0, len(keyValuePairs)/2)
+		for i := 1; i < len(keyValuePairs); i += 2 {
+			result = append(result, keyValuePairs[i])
+		}
+
+		return result
+	}
+
+	pairs, err := findRenames(compare.ctx, values(removals), values(additions), nil)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if len(pairs) == 0 {
+		return nil, removals, additions, nil
+	}
+
+	var result []Diff
+	for _, pair := range pairs {
+		fromKey, fromValue := removals[2*pair.from], removals[2*pair.from+1]
+		toKey, toValue := additions[2*pair.to], additions[2*pair.to+1]
+
+		keyPath := ytbx.NewPathWithNamedElement(path, fromKey.Value)
+		result = append(result, Diff{
+			Path: &keyPath,
+			Details: []Detail{{
+				Kind: RENAME,
+				From: fromKey,
+				To:   toKey,
+			}},
+		})
+
+		diffs, err := compare.objects(keyPath, followAlias(fromValue), followAlias(toValue))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		result = append(result, compare.withAnchorChanges(keyPath, fromValue, toValue, diffs)...)
+	}
+
+	remainingRemovals, remainingAdditions := remainingEntries(pairs, removals, additions, 2)
+	return result, remainingRemovals, remainingAdditions, nil
+}
+
+// similarListEntries compares removed and added entries of a list without
+// identifier that are similar enough to be considered as modified entries,
+// these are removed from the provided lists of removals and additions and
+// returned as a lookup of the added entry to the removed entry it was paired with
+func (compare *compare) similarListEntries(path ytbx.Path, from *yamlv3.Node, removals, additions []*yamlv3.Node) ([]Diff, map[*yamlv3.Node]*yamlv3.Node, []*yamlv3.Node, []*yamlv3.Node, error) {
+	threshold := compare.settings.FuzzyListPairingThreshold
+	if threshold <= 0 || len(removals) == 0 || len(additions) == 0 {
+		return nil, nil, removals, additions, nil
+	}
+
+	// duplicate entries cannot be paired, since their index is not unique
+	unique := func(nodes []*yamlv3.Node) []*yamlv3.Node {
+		count := map[*yamlv3.Node]int{}
+		for _, node := range nodes {
+			count[node]++
+		}
+
+		result := make([]*yamlv3.Node, len(nodes))
+		for i, node := range nodes {
+			result[i] = node
+			if count[node] > 1 {
+				result[i] = &yamlv3.Node{Kind: yamlv3.ScalarNode}
+			}
+		}
+
+		return result
+	}
+
+	pairs, err := findSimilarEntries(compare.ctx, unique(removals), unique(additions), nil, threshold)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	if len(pairs) == 0 {
+		return nil, nil, removals, additions, nil
+	}
+
+	var result []Diff
+	paired := make(map[*yamlv3.Node]*yamlv3.Node, len(pairs))
+	for _, pair := range pairs {
+		paired[additions[pair.to]] = removals[pair.from]
+
+		idx := 0
+		for i, entry := range from.Content {
+			if entry == removals[pair.from] {
+				idx = i
+				break
+			}
+		}
+
+		entryPath := ytbx.NewPathWithIndexedListElement(path, idx)
+		diffs, err := compare.objects(
+			entryPath,
+			followAlias(removals[pair.from]),
+			followAlias(additions[pair.to]),
+		)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+
+		result = append(result, compare.withAnchorChanges(entryPath, removals[pair.from], additions[pair.to], diffs)...)
+	}
+
+	remainingRemovals, remainingAdditions := remainingEntries(pairs, removals, additions, 1)
+	return result, paired, remainingRemovals, remainingAdditions, nil
+}
+
+// remainingEntries returns the removals and additions that are not part of a
+// rename pair, where an entry consists of the given number of nodes
+func remainingEntries(pairs []renamePair, removals, additions []*yamlv3.Node, size int) ([]*yamlv3.Node, []*yamlv3.Node) {
+	pairedFrom, pairedTo := map[int]struct{}{}, map[int]struct{}{}
+	for _, pair := range pairs {
+		pairedFrom[pair.from], pairedTo[pair.to] = struct{}{}, struct{}{}
+	}
+
+	filter := func(nodes []*yamlv3.Node, paired map[int]struct{}) []*yamlv3.Node {
+		result := make([]*yamlv3.Node, 0, len(nodes))
+		for i := 0; i < len(nodes); i += size {
+			if _, ok := paired[i/size]; !ok {
+				result = append(result, nodes[i:i+size]...)
+			}
+		}
+
+		return result
+	}
+
+	return filter(removals, pairedFrom), filter(additions, pairedTo)
+}
+
+// identifierFields returns the top-level fields that are used by the list item
+// identifier, which is empty for identifiers that rely on nested fields
+func identifierFields(identifier listItemIdentifier) []string {
+	switch identifier := identifier.(type) {
+	case *singleField:
+		return []string{identifier.IdentifierFieldName}
+
+	case *compositeField:
+		return identifier.IdentifierFieldNames
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// Notes of modifications of scalars that are no actual changes: the value was
+// only written differently, or it only changed within the configured tolerance
+const (
+	equivalentValueNote = "equivalent value"
+	withinToleranceNote = "within tolerance"
+)
+
+// semanticScalars compares two scalars by their value rather than by their
+// notation, in case both are numbers, timestamps, or booleans. Values that
+// were only reformatted or that only differ within the tolerance are reported
+// with a note, but do not count as a change (see isEquivalentValue).
+func (compare *compare) semanticScalars(path ytbx.Path, from *yamlv3.Node, to *yamlv3.Node) ([]Diff, bool) {
+	equivalent, ok := equivalentScalars(from, to, compare.settings.FloatTolerance)
+	if !ok {
+		return nil, false
+	}
+
+	var note string
+	switch identical, _ := equivalentScalars(from, to, 0); {
+	case identical && from.Value == to.Value && from.Tag == to.Tag:
+		return nil, true
+
+	case identical:
+		note = equivalentValueNote
+
+	case equivalent:
+		note = withinToleranceNote
+	}
+
+	return []Diff{{
+		&path,
+		[]Detail{{
+			Kind: MODIFICATION,
+			From: from,
+			To:   to,
+			Note: note,
+		}},
+	}}, true
+}
+
+// isEquivalentValue returns whether the detail is a modification of a scalar,
+// which still has an equivalent value, and therefore is no actual change
+func isEquivalentValue(detail Detail) bool {
+	return detail.Kind == MODIFICATION && (detail.Note == equivalentValueNote || detail.Note == withinToleranceNote)
+}
+
+// equivalentScalars checks whether both scalars have the same value, where the
+// second return value is false if the scalars cannot be compared by value
+func equivalentScalars(from *yamlv3.Node, to *yamlv3.Node, tolerance float64) (bool, bool) {
+	if from == nil || to == nil || from.Kind != yamlv3.ScalarNode || to.Kind != yamlv3.ScalarNode {
+		return false, false
+	}
+
+	switch {
+	case isNumberTag(from.Tag) && isNumberTag(to.Tag):
+		return equivalentNumbers(from, to, tolerance)
+
+	case from.Tag == "!!timestamp" && to.Tag == "!!timestamp":
+		var fromTime, toTime time.Time
+		if from.Decode(&fromTime) != nil || to.Decode(&toTime) != nil {
+			return false, false
+		}
+
+		return fromTime.Equal(toTime), true
+
+	case (from.Tag == "!!bool" || to.Tag == "!!bool") && isBoolLike(from) && isBoolLike(to):
+		fromValue, _ := toBool(from.Value)
+		toValue, _ := toBool(to.Value)
+		return fromValue == toValue, true
+	}
+
+	return false, false
+}
+
+// normalizedScalar returns a notation independent representation of the value
+// of the scalar, in case it is a number, a timestamp, or a boolean. Numbers are
+// represented as exact fractions, so that the notation matches the one of
+// parsed quantities.
+func normalizedScalar(node *yamlv3.Node) (string, bool) {
+	switch node.Tag {
+	case "!!int", "!!float":
+		if value, ok := toBigInt(node); ok {
+			return value.String(), true
+		}
+
+		if value, ok := new(big.Rat).SetString(node.Value); ok {
+			return value.RatString(), true
+		}
+
+		var value float64
+		if err := node.Decode(&value); err != nil {
+			return "", false
+		}
+
+		return strconv.FormatFloat(value, 'g', -1, 64), true
+
+	case "!!timestamp":
+		var value time.Time
+		if err := node.Decode(&value); err != nil {
+			return "", false
+		}
+
+		return value.UTC().Format(time.RFC3339Nano), true
+
+	case "!!bool":
+		value, err := toBool(node.Value)
+		if err != nil {
+			return "", false
+		}
+
+		return strconv.FormatBool(value), true
+	}
+
+	return "", false
+}
+
+func equivalentNumbers(from *yamlv3.Node, to *yamlv3.Node, tolerance float64) (bool, bool) {
+	// integers are compared without the detour via floats to not lose precision,
+	// this includes integers too large for int64, which are tagged as floats
+	fromInt, fromIsInt := toBigInt(from)
+	toInt, toIsInt := toBigInt(to)
+	if fromIsInt && toIsInt {
+		difference := new(big.Float).SetInt(new(big.Int).Abs(new(big.Int).Sub(fromInt, toInt)))
+		return difference.Cmp(big.NewFloat(tolerance)) <= 0, true
+	}
+
+	var fromFloat, toFloat float64
+	if from.Decode(&fromFloat) != nil || to.Decode(&toFloat) != nil {
+		return false, false
+	}
+
+	switch {
+	case math.IsNaN(fromFloat) || math.IsNaN(toFloat):
+		return math.IsNaN(fromFloat) && math.IsNaN(toFloat), true
+
+	case math.IsInf(fromFloat, 0) || math.IsInf(toFloat, 0):
+		return fromFloat == toFloat, true
+	}
+
+	return math.Abs(fromFloat-toFloat) <= tolerance, true
+}
+
+func toBigInt(node *yamlv3.Node) (*big.Int, bool) {
+	// base prefixes (0x, 0o, 0b) and underscores are supported by big.Int
+	if result, ok := new(big.Int).SetString(node.Value, 0); ok {
+		return result, true
+	}
+
+	var value interface{}
+	if err := node.Decode(&value); err != nil {
+		return nil, false
+	}
+
+	switch number := value.(type) {
+	case int:
+		return big.NewInt(int64(number)), true
+
+	case int64:
+		return big.NewInt(number), true
+
+	case uint64:
+		return new(big.Int).SetUint64(number), true
+	}
+
+	return nil, false
+}
+
+func isNumberTag(tag string) bool {
+	return tag == "!!int" || tag == "!!float"
+}
+
+// isBoolLike returns true for booleans, but also for strings that would have
+// been a boolean in YAML 1.1, for example `yes` or `off`
+func isBoolLike(node *yamlv3.Node) bool {
+	if node.Tag != "!!bool" && node.Tag != "!!str" {
+		return false
+	}
+
+	_, err := toBool(node.Value)
+	return err == nil
+}
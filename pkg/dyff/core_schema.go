@@ -0,0 +1,339 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// Schemas contains the list semantics declared in Kubernetes OpenAPI schemas
+// or CustomResourceDefinitions (`x-kubernetes-list-type` and
+// `x-kubernetes-list-map-keys`) by resource type (API version and kind)
+type Schemas struct {
+	kinds map[string]*schemaNode
+}
+
+// schemaNode is the part of an OpenAPI schema that is relevant to find the
+// list semantics for a path in a document
+type schemaNode struct {
+	properties           map[string]*schemaNode
+	additionalProperties *schemaNode
+	items                *schemaNode
+	allOf                []*schemaNode
+	listType             string
+	listMapKeys          []string
+}
+
+// LoadSchemas loads OpenAPI v2 or v3 schemas (JSON or YAML), for example the
+// output of `kubectl get --raw /openapi/v3/apis/apps/v1`, or files with
+// CustomResourceDefinitions. Locations can be files or directories.
+func LoadSchemas(locations ...string) (*Schemas, error) {
+	schemas := &Schemas{kinds: map[string]*schemaNode{}}
+
+	for _, location := range locations {
+		inputFile, err := ytbx.LoadFile(location)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, document := range inputFile.Documents {
+			if isEmptyDocument(document) {
+				continue
+			}
+
+			if err := schemas.add(document.Content[0]); err != nil {
+				return nil, fmt.Errorf("failed to load schema from %s: %w", location, err)
+			}
+		}
+	}
+
+	return schemas, nil
+}
+
+func (schemas *Schemas) add(node *yamlv3.Node) error {
+	if node.Kind != yamlv3.MappingNode {
+		return fmt.Errorf("unsupported schema document, expected a map")
+	}
+
+	if kind, ok := findValueByKey(node, "kind"); ok && kind.Value == "CustomResourceDefinition" {
+		return schemas.addCustomResourceDefinition(node)
+	}
+
+	// OpenAPI v3 has the schemas in the components section, v2 has definitions
+	definitions, ok := findValueByKey(node, "components")
+	if ok {
+		definitions, ok = findValueByKey(definitions, "schemas")
+	} else {
+		definitions, ok = findValueByKey(node, "definitions")
+	}
+
+	if !ok || definitions.Kind != yamlv3.MappingNode {
+		return fmt.Errorf("unsupported schema document, neither CustomResourceDefinition nor OpenAPI schema")
+	}
+
+	return schemas.addOpenAPIDefinitions(definitions)
+}
+
+func (schemas *Schemas) addCustomResourceDefinition(node *yamlv3.Node) error {
+	group, err := grab(node, "spec.group")
+	if err != nil {
+		return err
+	}
+
+	kind, err := grab(node, "spec.names.kind")
+	if err != nil {
+		return err
+	}
+
+	versions, err := grab(node, "spec.versions")
+	if err != nil {
+		return err
+	}
+
+	for _, version := range versions.Content {
+		name, ok := findValueByKey(version, "name")
+		if !ok {
+			return fmt.Errorf("failed to find name of version in CustomResourceDefinition")
+		}
+
+		schema, err := grab(version, "schema.openAPIV3Schema")
+		if err != nil {
+			continue
+		}
+
+		resolver := &schemaResolver{}
+		schemas.kinds[kindKey(group.Value, name.Value, kind.Value)] = resolver.convert(schema)
+	}
+
+	return nil
+}
+
+func (schemas *Schemas) addOpenAPIDefinitions(definitions *yamlv3.Node) error {
+	resolver := &schemaResolver{
+		raw:       map[string]*yamlv3.Node{},
+		converted: map[string]*schemaNode{},
+	}
+
+	for i := 0; i < len(definitions.Content); i += 2 {
+		resolver.raw[definitions.Content[i].Value] = definitions.Content[i+1]
+	}
+
+	for i := 0; i < len(definitions.Content); i += 2 {
+		gvks, ok := findValueByKey(definitions.Content[i+1], "x-kubernetes-group-version-kind")
+		if !ok {
+			continue
+		}
+
+		for _, gvk := range gvks.Content {
+			var group, version, kind string
+			if node, ok := findValueByKey(gvk, "group"); ok {
+				group = node.Value
+			}
+
+			if node, ok := findValueByKey(gvk, "version"); ok {
+				version = node.Value
+			}
+
+			if node, ok := findValueByKey(gvk, "kind"); ok {
+				kind = node.Value
+			}
+
+			schemas.kinds[kindKey(group, version, kind)] = resolver.definition(definitions.Content[i].Value)
+		}
+	}
+
+	return nil
+}
+
+// schemaResolver converts raw schema nodes and resolves references, since
+// schemas can be recursive, every definition is only converted once
+type schemaResolver struct {
+	raw       map[string]*yamlv3.Node
+	converted map[string]*schemaNode
+}
+
+func (resolver *schemaResolver) definition(name string) *schemaNode {
+	if result, ok := resolver.converted[name]; ok {
+		return result
+	}
+
+	raw, ok := resolver.raw[name]
+	if !ok {
+		return nil
+	}
+
+	// register the result before the conversion to support recursive schemas
+	result := &schemaNode{}
+	resolver.converted[name] = result
+	*result = *resolver.convert(raw)
+
+	return result
+}
+
+func (resolver *schemaResolver) convert(node *yamlv3.Node) *schemaNode {
+	result := &schemaNode{}
+	if node == nil || node.Kind != yamlv3.MappingNode {
+		return result
+	}
+
+	for i := 0; i < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+
+		switch key {
+		case "$ref":
+			name := value.Value[strings.LastIndex(value.Value, "/")+1:]
+			if ref := resolver.definition(name); ref != nil {
+				result.allOf = append(result.allOf, ref)
+			}
+
+		case "allOf":
+			for _, entry := range value.Content {
+				result.allOf = append(result.allOf, resolver.convert(entry))
+			}
+
+		case "properties":
+			result.properties = map[string]*schemaNode{}
+			for j := 0; j < len(value.Content); j += 2 {
+				result.properties[value.Content[j].Value] = resolver.convert(value.Content[j+1])
+			}
+
+		case "additionalProperties":
+			if value.Kind == yamlv3.MappingNode {
+				result.additionalProperties = resolver.convert(value)
+			}
+
+		case "items":
+			result.items = resolver.convert(value)
+
+		case "x-kubernetes-list-type":
+			result.listType = value.Value
+
+		case "x-kubernetes-list-map-keys":
+			for _, entry := range value.Content {
+				result.listMapKeys = append(result.listMapKeys, entry.Value)
+			}
+		}
+	}
+
+	return result
+}
+
+// child returns the schema of the entry with the given name in a map, or the
+// schema of the list entries in case the schema is a list
+func (node *schemaNode) child(name string) *schemaNode {
+	if node.items != nil {
+		return node.items
+	}
+
+	if result, ok := node.properties[name]; ok {
+		return result
+	}
+
+	for _, entry := range node.allOf {
+		if result := entry.child(name); result != nil {
+			return result
+		}
+	}
+
+	return node.additionalProperties
+}
+
+func (node *schemaNode) listSemantics() (string, []string) {
+	if node.listType != "" {
+		return node.listType, node.listMapKeys
+	}
+
+	for _, entry := range node.allOf {
+		if listType, listMapKeys := entry.listSemantics(); listType != "" {
+			return listType, listMapKeys
+		}
+	}
+
+	return "", nil
+}
+
+// lookup returns the list type and map keys declared for the list at the given
+// path, or an empty list type if there is no schema for the path
+func (schemas *Schemas) lookup(path ytbx.Path) (string, []string) {
+	if schemas == nil || path.Root == nil || path.DocumentIdx >= len(path.Root.Documents) {
+		return "", nil
+	}
+
+	document := path.Root.Documents[path.DocumentIdx]
+	if document.Kind == yamlv3.DocumentNode && len(document.Content) > 0 {
+		document = document.Content[0]
+	}
+
+	apiVersion, ok := findValueByKey(document, "apiVersion")
+	if !ok {
+		return "", nil
+	}
+
+	kind, ok := findValueByKey(document, "kind")
+	if !ok {
+		return "", nil
+	}
+
+	node, ok := schemas.kinds[apiVersion.Value+"/"+kind.Value]
+	if !ok {
+		return "", nil
+	}
+
+	// the schema defines whether a path element refers to a map or a list
+	// entry, therefore the list entry identifier does not matter here
+	for _, element := range path.PathElements {
+		if node = node.child(element.Name); node == nil {
+			return "", nil
+		}
+	}
+
+	return node.listSemantics()
+}
+
+func kindKey(group, version, kind string) string {
+	if group == "" {
+		return version + "/" + kind
+	}
+
+	return group + "/" + version + "/" + kind
+}
+
+// ignoreOrderChangesAt returns whether order changes in the list at the given
+// path are irrelevant, which is the case if a list order rule marks the list as
+// a set, or if no rule applies and it is configured or the schema declares a set
+func (compare *compare) ignoreOrderChangesAt(path ytbx.Path) bool {
+	for _, rule := range compare.settings.ListOrderRules {
+		if newPathPattern(rule.PathPattern).matches(path) {
+			return !rule.Ordered
+		}
+	}
+
+	if compare.settings.IgnoreOrderChanges {
+		return true
+	}
+
+	listType, _ := compare.settings.Schemas.lookup(path)
+	return listType == "set"
+}
@@ -0,0 +1,435 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// documentSequences compares input files that do not have the same number of
+// documents. Identical documents serve as anchors (longest common subsequence),
+// the documents in between are aligned with each other to find out which of
+// them were modified, added, or removed.
+func (compare *compare) documentSequences(from, to ytbx.InputFile) ([]Diff, error) {
+	fromHashes, err := compare.documentHashes(from)
+	if err != nil {
+		return nil, err
+	}
+
+	toHashes, err := compare.documentHashes(to)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := longestCommonSubsequence(compare.ctx, fromHashes, toHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	// add an artificial anchor at the end to also process the trailing documents
+	anchors := append(matches, [2]int{len(fromHashes), len(toHashes)})
+
+	var result []Diff
+	var fromIdx, toIdx int
+	for _, anchor := range anchors {
+		diffs, err := compare.documentGap(from, to, fromIdx, anchor[0], toIdx, anchor[1])
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, diffs...)
+		fromIdx, toIdx = anchor[0]+1, anchor[1]+1
+	}
+
+	return result, nil
+}
+
+// documentGap compares the documents between two anchors. In case both sides
+// have the same number of documents, they are compared by position. Otherwise,
+// the documents are paired by the similarity of their content and the remaining
+// documents are reported as removals (with the index of the `from` input file)
+// or additions (with the index of the `to` input file).
+func (compare *compare) documentGap(from, to ytbx.InputFile, fromStart, fromEnd, toStart, toEnd int) ([]Diff, error) {
+	if fromEnd-fromStart == toEnd-toStart {
+		pairs := make([]documentPair, fromEnd-fromStart)
+		for i := range pairs {
+			pairs[i] = documentPair{
+				path: ytbx.Path{Root: &from, DocumentIdx: fromStart + i},
+				from: from.Documents[fromStart+i],
+				to:   to.Documents[toStart+i],
+			}
+		}
+
+		return compare.documentPairs(pairs)
+	}
+
+	var removals, additions []int
+	for i := fromStart; i < fromEnd; i++ {
+		if !compare.isAbsent(from.Documents[i]) {
+			removals = append(removals, i)
+		}
+	}
+
+	for i := toStart; i < toEnd; i++ {
+		if !compare.isAbsent(to.Documents[i]) {
+			additions = append(additions, i)
+		}
+	}
+
+	renames, err := findRenames(compare.ctx, documentContents(from, removals), documentContents(to, additions), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// The pairs are sorted by similarity, use the document order instead
+	sort.SliceStable(renames, func(i, j int) bool {
+		return renames[i].from < renames[j].from
+	})
+
+	pairs := make([]documentPair, len(renames))
+	removed, added := map[int]struct{}{}, map[int]struct{}{}
+	for i, rename := range renames {
+		fromIdx, toIdx := removals[rename.from], additions[rename.to]
+		pairs[i] = documentPair{
+			path: ytbx.Path{Root: &from, DocumentIdx: fromIdx},
+			from: from.Documents[fromIdx],
+			to:   to.Documents[toIdx],
+		}
+
+		removed[rename.from], added[rename.to] = struct{}{}, struct{}{}
+	}
+
+	result, err := compare.documentPairs(pairs)
+	if err != nil {
+		return nil, err
+	}
+
+	// the document index of removals and additions refers to different input
+	// files, which is why the side is part of the description of the document
+	fromSide, toSide := withDocumentSide(from, "from"), withDocumentSide(to, "to")
+
+	for i, idx := range removals {
+		if _, ok := removed[i]; ok {
+			continue
+		}
+
+		result = append(result, Diff{
+			Path: &ytbx.Path{Root: fromSide, DocumentIdx: idx},
+			Details: []Detail{{
+				Kind: REMOVAL,
+				From: from.Documents[idx],
+				To:   nil,
+			}},
+		})
+	}
+
+	for i, idx := range additions {
+		if _, ok := added[i]; ok {
+			continue
+		}
+
+		result = append(result, Diff{
+			Path: &ytbx.Path{Root: toSide, DocumentIdx: idx},
+			Details: []Detail{{
+				Kind: ADDITION,
+				From: nil,
+				To:   to.Documents[idx],
+			}},
+		})
+	}
+
+	return result, nil
+}
+
+// documentContents returns the content of the documents with the given indices
+func documentContents(inputFile ytbx.InputFile, indices []int) []*yamlv3.Node {
+	result := make([]*yamlv3.Node, len(indices))
+	for i, idx := range indices {
+		result[i] = documentContent(inputFile.Documents[idx])
+	}
+
+	return result
+}
+
+// withDocumentSide returns a copy of the input file, which describes its
+// documents including the side of the comparison, e.g. `document #2 (to)`,
+// unless the documents have names
+func withDocumentSide(inputFile ytbx.InputFile, side string) *ytbx.InputFile {
+	if len(inputFile.Names) == len(inputFile.Documents) {
+		return &inputFile
+	}
+
+	inputFile.Names = make([]string, len(inputFile.Documents))
+	for i := range inputFile.Names {
+		inputFile.Names[i] = fmt.Sprintf("document #%d (%s)", i+1, side)
+	}
+
+	return &inputFile
+}
+
+func (compare *compare) documentHashes(inputFile ytbx.InputFile) ([]uint64, error) {
+	result := make([]uint64, len(inputFile.Documents))
+	for i, document := range inputFile.Documents {
+		if err := compare.ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if document.Kind == yamlv3.DocumentNode && len(document.Content) > 0 {
+			result[i] = compare.calcNodeHash(ytbx.Path{Root: &inputFile, DocumentIdx: i}, document.Content[0])
+		}
+	}
+
+	return result, nil
+}
+
+// longestCommonSubsequence returns the index pairs of the entries that are part
+// of the longest common subsequence of both lists. The common prefix and suffix
+// are matched right away, the remaining entries are matched using Hirschberg's
+// algorithm, which only requires linear space.
+func longestCommonSubsequence(ctx context.Context, a, b []uint64) ([][2]int, error) {
+	var prefix int
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+
+	var suffix int
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	var result [][2]int
+	for i := 0; i < prefix; i++ {
+		result = append(result, [2]int{i, i})
+	}
+
+	lcs := hirschberg{ctx: ctx, a: a, b: b}
+	if err := lcs.match(prefix, len(a)-suffix, prefix, len(b)-suffix, &result); err != nil {
+		return nil, err
+	}
+
+	for i := suffix; i > 0; i-- {
+		result = append(result, [2]int{len(a) - i, len(b) - i})
+	}
+
+	return result, nil
+}
+
+// hirschberg finds the longest common subsequence of two lists by splitting
+// the first list in half and finding the position in the second list where the
+// longest common subsequences of both halves meet, recursively
+type hirschberg struct {
+	ctx  context.Context
+	a, b []uint64
+}
+
+// match appends the index pairs of the longest common subsequence of a[aStart:aEnd]
+// and b[bStart:bEnd] to the result in ascending order
+func (lcs hirschberg) match(aStart, aEnd, bStart, bEnd int, result *[][2]int) error {
+	switch {
+	case aStart >= aEnd || bStart >= bEnd:
+		return nil
+
+	case aEnd-aStart == 1:
+		for j := bStart; j < bEnd; j++ {
+			if lcs.a[aStart] == lcs.b[j] {
+				*result = append(*result, [2]int{aStart, j})
+				break
+			}
+		}
+
+		return nil
+	}
+
+	aMid := aStart + (aEnd-aStart)/2
+
+	forward, err := lcs.forwardLengths(aStart, aMid, bStart, bEnd)
+	if err != nil {
+		return err
+	}
+
+	backward, err := lcs.backwardLengths(aMid, aEnd, bStart, bEnd)
+	if err != nil {
+		return err
+	}
+
+	// split the second list where both halves share the most entries
+	bMid, best := bStart, -1
+	for j := 0; j <= bEnd-bStart; j++ {
+		if length := forward[j] + backward[j]; length > best {
+			bMid, best = bStart+j, length
+		}
+	}
+
+	if err := lcs.match(aStart, aMid, bStart, bMid, result); err != nil {
+		return err
+	}
+
+	return lcs.match(aMid, aEnd, bMid, bEnd, result)
+}
+
+// forwardLengths returns the lengths of the longest common subsequences of
+// a[aStart:aEnd] and b[bStart:bStart+j] for every j
+func (lcs hirschberg) forwardLengths(aStart, aEnd, bStart, bEnd int) ([]int, error) {
+	previous, current := make([]int, bEnd-bStart+1), make([]int, bEnd-bStart+1)
+	for i := aStart; i < aEnd; i++ {
+		if err := lcs.ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		for j := 1; j <= bEnd-bStart; j++ {
+			if lcs.a[i] == lcs.b[bStart+j-1] {
+				current[j] = previous[j-1] + 1
+			} else {
+				current[j] = max(previous[j], current[j-1])
+			}
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous, nil
+}
+
+// backwardLengths returns the lengths of the longest common subsequences of
+// a[aStart:aEnd] and b[bStart+j:bEnd] for every j
+func (lcs hirschberg) backwardLengths(aStart, aEnd, bStart, bEnd int) ([]int, error) {
+	previous, current := make([]int, bEnd-bStart+1), make([]int, bEnd-bStart+1)
+	for i := aEnd - 1; i >= aStart; i-- {
+		if err := lcs.ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		for j := bEnd - bStart - 1; j >= 0; j-- {
+			if lcs.a[i] == lcs.b[bStart+j] {
+				current[j] = previous[j+1] + 1
+			} else {
+				current[j] = max(previous[j], current[j+1])
+			}
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous, nil
+}
+
+// positionalLists compares lists entry by entry, where identical entries serve
+// as anchors (longest common subsequence) and the entries in between are
+// reported as removals or additions at their respective index. Entries that
+// were replaced at the same position are compared with each other.
+func (compare *compare) positionalLists(path ytbx.Path, from *yamlv3.Node, to *yamlv3.Node) ([]Diff, error) {
+	fromHashes, err := compare.listEntryHashes(path, from)
+	if err != nil {
+		return nil, err
+	}
+
+	toHashes, err := compare.listEntryHashes(path, to)
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := longestCommonSubsequence(compare.ctx, fromHashes, toHashes)
+	if err != nil {
+		return nil, err
+	}
+
+	// add an artificial anchor at the end to also process the trailing entries
+	anchors := append(matches, [2]int{len(fromHashes), len(toHashes)})
+
+	var result []Diff
+	var fromIdx, toIdx int
+	for _, anchor := range anchors {
+		removals, additions := from.Content[fromIdx:anchor[0]], to.Content[toIdx:anchor[1]]
+
+		// entries at the same position within the gap are modifications
+		paired := min(len(removals), len(additions))
+		for i := 0; i < paired; i++ {
+			entryPath := ytbx.NewPathWithIndexedListElement(path, fromIdx+i)
+			diffs, err := compare.objects(
+				entryPath,
+				followAlias(removals[i]),
+				followAlias(additions[i]),
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, compare.withAnchorChanges(entryPath, removals[i], additions[i], diffs)...)
+		}
+
+		// only one side can have remaining entries, removals refer to the index
+		// in the old list, additions refer to the index in the new list
+		switch {
+		case len(removals) > paired:
+			entryPath := ytbx.NewPathWithIndexedListElement(path, fromIdx+paired)
+			result = append(result, Diff{
+				Path: &entryPath,
+				Details: []Detail{{
+					Kind: REMOVAL,
+					From: &yamlv3.Node{
+						Kind:    yamlv3.SequenceNode,
+						Tag:     "!!seq",
+						Content: removals[paired:],
+					},
+					To: nil,
+				}},
+			})
+
+		case len(additions) > paired:
+			entryPath := ytbx.NewPathWithIndexedListElement(path, toIdx+paired)
+			result = append(result, Diff{
+				Path: &entryPath,
+				Details: []Detail{{
+					Kind: ADDITION,
+					From: nil,
+					To: &yamlv3.Node{
+						Kind:    yamlv3.SequenceNode,
+						Tag:     "!!seq",
+						Content: additions[paired:],
+					},
+				}},
+			})
+		}
+
+		// identical entries can still differ in their comments, anchors, and aliases
+		if anchor[0] < len(from.Content) && (compare.settings.CompareComments || compare.settings.ReportAnchorChanges) {
+			diffs, err := compare.unchangedEntryChanges(
+				ytbx.NewPathWithIndexedListElement(path, anchor[0]),
+				from.Content[anchor[0]],
+				to.Content[anchor[1]],
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, diffs...)
+		}
+
+		fromIdx, toIdx = anchor[0]+1, anchor[1]+1
+	}
+
+	return result, nil
+}
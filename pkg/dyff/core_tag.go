@@ -0,0 +1,135 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"strings"
+	"sync"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// TagNormalizer translates a node with a custom tag into an equivalent node
+// without the custom tag, for example the short form of a CloudFormation
+// intrinsic function (`!Ref X`) into its long form (`{Ref: X}`), so that both
+// forms are considered to be equal
+type TagNormalizer func(node *yamlv3.Node) *yamlv3.Node
+
+var tagNormalizers = struct {
+	sync.RWMutex
+	byTag map[string]TagNormalizer
+}{byTag: map[string]TagNormalizer{}}
+
+// RegisterTagNormalizer registers a normalizer for nodes with the given tag
+// (e.g. `!Vault`), which is used if tags are configured to be normalized. An
+// already registered normalizer for the same tag is replaced.
+func RegisterTagNormalizer(tag string, normalizer TagNormalizer) {
+	tagNormalizers.Lock()
+	defer tagNormalizers.Unlock()
+
+	tagNormalizers.byTag[tag] = normalizer
+}
+
+func init() {
+	// CloudFormation intrinsic functions and condition functions, see
+	// https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/intrinsic-function-reference.html
+	RegisterTagNormalizer("!Ref", longFormFunction("Ref"))
+	RegisterTagNormalizer("!Condition", longFormFunction("Condition"))
+	RegisterTagNormalizer("!GetAtt", getAttFunction)
+	for _, name := range []string{
+		"And", "Base64", "Cidr", "Equals", "FindInMap", "GetAZs", "If",
+		"ImportValue", "Join", "Not", "Or", "Select", "Split", "Sub",
+		"Transform", "ToJsonString", "Length",
+	} {
+		RegisterTagNormalizer("!"+name, longFormFunction("Fn::"+name))
+	}
+}
+
+// longFormFunction returns a normalizer that translates the tagged node into a
+// map with the given name as the only key and the untagged node as its value
+func longFormFunction(name string) TagNormalizer {
+	return func(node *yamlv3.Node) *yamlv3.Node {
+		return &yamlv3.Node{
+			Kind: yamlv3.MappingNode,
+			Tag:  "!!map",
+			Content: []*yamlv3.Node{
+				{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: name},
+				untagged(node),
+			},
+		}
+	}
+}
+
+// getAttFunction translates the short form of `GetAtt`, which can also be
+// written as `!GetAtt resource.attribute`, into its long form
+func getAttFunction(node *yamlv3.Node) *yamlv3.Node {
+	value := untagged(node)
+	if node.Kind == yamlv3.ScalarNode {
+		resource, attribute, _ := strings.Cut(node.Value, ".")
+		value = &yamlv3.Node{
+			Kind: yamlv3.SequenceNode,
+			Tag:  "!!seq",
+			Content: []*yamlv3.Node{
+				{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: resource},
+				{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: attribute},
+			},
+		}
+	}
+
+	return longFormFunction("Fn::GetAtt")(value)
+}
+
+// untagged returns a copy of the node with the tag that the node would have
+// without the custom tag
+func untagged(node *yamlv3.Node) *yamlv3.Node {
+	result := *node
+	result.Tag = ""
+	result.Tag = result.ShortTag()
+	return &result
+}
+
+// isCustomTag returns whether the tag is an application specific tag, which is
+// neither one of the standard YAML tags, nor the non-specific tag
+func isCustomTag(tag string) bool {
+	return tag != "" && tag != "!" && !strings.HasPrefix(tag, "!!")
+}
+
+// normalizeTag returns the normalized node in case the node has a custom tag
+// with a registered normalizer, otherwise the node itself
+func (compare *compare) normalizeTag(node *yamlv3.Node) *yamlv3.Node {
+	if !compare.settings.NormalizeTags || node == nil || !isCustomTag(node.Tag) {
+		return node
+	}
+
+	tagNormalizers.RLock()
+	normalizer, ok := tagNormalizers.byTag[node.Tag]
+	tagNormalizers.RUnlock()
+
+	if !ok {
+		return node
+	}
+
+	if result := normalizer(node); result != nil {
+		return result
+	}
+
+	return node
+}
@@ -33,16 +33,27 @@ const (
 	REMOVAL      = '-'
 	MODIFICATION = '±'
 	ORDERCHANGE  = '⇆'
+	RENAME       = '→'
+	MOVE         = '⇢'
+	COMMENT      = '#'
+	ANCHOR       = '&'
 	// ILLEGAL      = '✕'
 	// ATTENTION    = '⚠'
 )
 
 // Detail encapsulate the actual details of a change, mainly the kind of
-// difference and the values
+// difference and the values. The optional note contains additional details
+// about the change, for example the delta of two Kubernetes quantities. The
+// source is the original path of content that was moved to the path of the diff.
+// The embedding is the path of the string that contains the document in which
+// the change is located, in case it is an embedded document.
 type Detail struct {
-	From *yamlv3.Node
-	To   *yamlv3.Node
-	Kind rune
+	From      *yamlv3.Node
+	To        *yamlv3.Node
+	Kind      rune
+	Note      string
+	Source    *ytbx.Path
+	Embedding *ytbx.Path
 }
 
 type K8sMetadata struct {
@@ -57,6 +68,18 @@ type Diff struct {
 	Details []Detail
 }
 
+// embedding returns the path of the string that contains the embedded document
+// in which the difference is located, or nil if it is not an embedded document
+func (diff Diff) embedding() *ytbx.Path {
+	for _, detail := range diff.Details {
+		if detail.Embedding != nil {
+			return detail.Embedding
+		}
+	}
+
+	return nil
+}
+
 // Report encapsulates the actual end-result of the comparison: The input data
 // and the list of differences
 type Report struct {
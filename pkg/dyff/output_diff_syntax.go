@@ -26,6 +26,8 @@ import (
 	"fmt"
 	"io"
 	"strings"
+
+	"github.com/gonvenience/ytbx"
 )
 
 // DiffSyntaxReport is a reporter with human readable output in mind
@@ -60,10 +62,10 @@ func (report *DiffSyntaxReport) WriteReport(out io.Writer) error {
 func (report *DiffSyntaxReport) generateDiffSyntaxDiffOutput(output stringWriter, diff Diff, useGoPatchPaths bool, showPathRoot bool) error {
 	_, _ = output.WriteString(fmt.Sprintf("\n%s ", report.PathPrefix))
 	if useGoPatchPaths {
-		_, _ = output.WriteString(styledGoPatchPath(diff.Path))
+		_, _ = output.WriteString(styledGoPatchPath(diff.Path, diff.embedding()))
 
 	} else {
-		_, _ = output.WriteString(styledDotStylePath(diff.Path))
+		_, _ = output.WriteString(styledDotStylePath(diff.Path, diff.embedding()))
 	}
 	// Only @@ also needs a postfix
 	if report.PathPrefix == "@@" {
@@ -78,7 +80,7 @@ func (report *DiffSyntaxReport) generateDiffSyntaxDiffOutput(output stringWriter
 
 	blocks := make([]string, len(diff.Details))
 	for i, detail := range diff.Details {
-		generatedOutput, err := report.generateDiffSyntaxDetailOutput(detail)
+		generatedOutput, err := report.generateDiffSyntaxDetailOutput(diff.Path, detail)
 		if err != nil {
 			return err
 		}
@@ -98,7 +100,7 @@ func (report *DiffSyntaxReport) generateDiffSyntaxDiffOutput(output stringWriter
 }
 
 // generatedyffSyntaxDetailOutput only serves as a dispatcher to call the correct sub function for the respective type of change
-func (report *DiffSyntaxReport) generateDiffSyntaxDetailOutput(detail Detail) (string, error) {
+func (report *DiffSyntaxReport) generateDiffSyntaxDetailOutput(path *ytbx.Path, detail Detail) (string, error) {
 	switch detail.Kind {
 	case ADDITION:
 		detailOutput, err := report.generateHumanDetailOutputAddition(detail)
@@ -131,6 +133,34 @@ func (report *DiffSyntaxReport) generateDiffSyntaxDetailOutput(detail Detail) (s
 			return "", err
 		}
 		return report.prefixChangeType(detailOutput), nil
+
+	case RENAME:
+		detailOutput, err := report.generateHumanDetailOutputRename(detail)
+		if err != nil {
+			return "", err
+		}
+		return report.prefixChangeType(detailOutput), nil
+
+	case MOVE:
+		detailOutput, err := report.generateHumanDetailOutputMove(path, detail)
+		if err != nil {
+			return "", err
+		}
+		return report.prefixChangeType(detailOutput), nil
+
+	case COMMENT:
+		detailOutput, err := report.generateHumanDetailOutputComment(detail)
+		if err != nil {
+			return "", err
+		}
+		return report.prefixChangeType(detailOutput), nil
+
+	case ANCHOR:
+		detailOutput, err := report.generateHumanDetailOutputAnchor(detail)
+		if err != nil {
+			return "", err
+		}
+		return report.prefixChangeType(detailOutput), nil
 	}
 
 	return "", fmt.Errorf("unsupported detail type %c", detail.Kind)
@@ -0,0 +1,293 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// GoPatchReport writes the differences as a go-patch operations file, which
+// can be used as a BOSH ops file to transform the `from` document into the
+// `to` document. Named list entries are referenced using `name=` style
+// selectors, additions use optional path segments (`?`). In case of input
+// files with multiple documents, there is one operations file per document.
+type GoPatchReport struct {
+	Report
+}
+
+// WriteReport writes the go-patch operations of each document to the writer
+func (report *GoPatchReport) WriteReport(out io.Writer) error {
+	patches, err := report.GoPatches()
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(out)
+	defer writer.Flush()
+
+	for _, operations := range patches {
+		if len(patches) > 1 {
+			_, _ = writer.WriteString("---\n")
+		}
+
+		if len(operations) == 0 {
+			_, _ = writer.WriteString("[]\n")
+			continue
+		}
+
+		encoder := yamlv3.NewEncoder(writer)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(operations); err != nil {
+			return err
+		}
+
+		if err := encoder.Close(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GoPatches returns the go-patch operations for each document of the `from`
+// input file, which transform the document into its counterpart
+func (report Report) GoPatches() ([][]GoPatchOperation, error) {
+	patches, err := report.JSONPatches()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([][]GoPatchOperation, len(patches))
+	for idx, operations := range patches {
+		converter := &goPatchConverter{
+			root:     copyNode(documentContent(report.From.Documents[idx]), nil),
+			identify: newCompare(context.Background()),
+		}
+
+		for _, operation := range operations {
+			if err := converter.convert(operation); err != nil {
+				return nil, err
+			}
+		}
+
+		result[idx] = converter.operations
+	}
+
+	return result, nil
+}
+
+// goPatchConverter translates JSON Patch operations into go-patch operations,
+// which refer to named list entries by name rather than by index. The JSON
+// Patch operations are applied to a copy of the document one after another to
+// know the list entries at the time of each operation.
+type goPatchConverter struct {
+	root       *yamlv3.Node
+	identify   *compare
+	operations []GoPatchOperation
+}
+
+func (converter *goPatchConverter) convert(operation JSONPatchOperation) error {
+	path, err := parsePointer(operation.Path)
+	if err != nil {
+		return err
+	}
+
+	switch operation.Op {
+	case "add":
+		if err := converter.add(path, operation.Value); err != nil {
+			return err
+		}
+
+	case "remove":
+		converter.operations = append(converter.operations, GoPatchOperation{
+			Type: "remove",
+			Path: converter.path(path),
+		})
+
+	case "replace":
+		converter.operations = append(converter.operations, GoPatchOperation{
+			Type:  "replace",
+			Path:  converter.path(path),
+			Value: copyNode(operation.Value, nil),
+		})
+
+	case "move":
+		// go-patch has no move operation, it is a removal and an addition
+		from, err := parsePointer(operation.From)
+		if err != nil {
+			return err
+		}
+
+		value, err := lookupNode(converter.root, from)
+		if err != nil {
+			return err
+		}
+
+		value = copyNode(value, nil)
+		if err := converter.convert(JSONPatchOperation{Op: "remove", Path: operation.From}); err != nil {
+			return err
+		}
+
+		return converter.convert(JSONPatchOperation{Op: "add", Path: operation.Path, Value: value})
+
+	default:
+		return fmt.Errorf("unsupported JSON patch operation %q", operation.Op)
+	}
+
+	return applyOperation(&converter.root, operation)
+}
+
+// add creates a replace operation with an optional path segment for new map
+// entries and named list entries, other list entries are inserted before the
+// entry at the respective index or appended
+func (converter *goPatchConverter) add(path []string, value *yamlv3.Node) error {
+	if len(path) == 0 {
+		converter.operations = append(converter.operations, GoPatchOperation{Type: "replace", Path: "/", Value: copyNode(value, nil)})
+		return nil
+	}
+
+	parentPath, token := path[:len(path)-1], path[len(path)-1]
+	parent, err := lookupNode(converter.root, parentPath)
+	if err != nil {
+		return err
+	}
+
+	prefix := strings.TrimSuffix(converter.path(parentPath), "/")
+
+	var target string
+	switch parent.Kind {
+	case yamlv3.MappingNode:
+		target = prefix + "/" + escapeGoPatch(token) + "?"
+
+	case yamlv3.SequenceNode:
+		idx, err := sequenceIndex(parent, token, true)
+		if err != nil {
+			return err
+		}
+
+		list := &yamlv3.Node{Kind: yamlv3.SequenceNode, Content: append(append([]*yamlv3.Node{}, parent.Content...), value)}
+		field, named := converter.identifier(list)
+
+		switch {
+		case idx == len(parent.Content) && named:
+			target = prefix + "/" + converter.selector(field, list, len(parent.Content)) + "?"
+
+		case idx == len(parent.Content):
+			target = prefix + "/-"
+
+		default:
+			target = prefix + "/" + converter.selector(field, parent, idx) + ":before"
+		}
+
+	default:
+		return fmt.Errorf("failed to add %q, parent is neither a map nor a list", pointer(path))
+	}
+
+	converter.operations = append(converter.operations, GoPatchOperation{Type: "replace", Path: target, Value: copyNode(value, nil)})
+	return nil
+}
+
+// path translates the JSON Pointer tokens into a go-patch path using the
+// current state of the document
+func (converter *goPatchConverter) path(tokens []string) string {
+	if len(tokens) == 0 {
+		return "/"
+	}
+
+	var buf strings.Builder
+	node := converter.root
+	for _, token := range tokens {
+		buf.WriteString("/")
+
+		switch node.Kind {
+		case yamlv3.MappingNode:
+			buf.WriteString(escapeGoPatch(token))
+			if idx := mappingKeyIndex(node, token); idx >= 0 {
+				node = node.Content[idx+1]
+			}
+
+		case yamlv3.SequenceNode:
+			idx, err := strconv.Atoi(token)
+			if err != nil || idx < 0 || idx >= len(node.Content) {
+				buf.WriteString(token)
+				continue
+			}
+
+			field, _ := converter.identifier(node)
+			buf.WriteString(converter.selector(field, node, idx))
+			node = node.Content[idx]
+
+		default:
+			buf.WriteString(escapeGoPatch(token))
+		}
+	}
+
+	return buf.String()
+}
+
+// identifier returns the field that uniquely identifies the entries of the
+// list, go-patch only supports fields on the first level of list entries
+func (converter *goPatchConverter) identifier(list *yamlv3.Node) (string, bool) {
+	identifier, err := converter.identify.getIdentifierFromNamedLists(list, list)
+	if err != nil {
+		return "", false
+	}
+
+	field, ok := identifier.(*singleField)
+	if !ok || strings.Contains(field.IdentifierFieldName, ".") {
+		return "", false
+	}
+
+	return field.IdentifierFieldName, true
+}
+
+// selector returns the go-patch path segment of the list entry at the given
+// index, which is a `field=value` selector in case the value is unique in the
+// list, since go-patch rejects ambiguous selectors, otherwise it is the index
+func (converter *goPatchConverter) selector(field string, list *yamlv3.Node, idx int) string {
+	if field == "" {
+		return strconv.Itoa(idx)
+	}
+
+	value, ok := findValueByKey(followAlias(list.Content[idx]), field)
+	if !ok || value.Kind != yamlv3.ScalarNode {
+		return strconv.Itoa(idx)
+	}
+
+	for i, entry := range list.Content {
+		if other, ok := findValueByKey(followAlias(entry), field); ok && i != idx && other.Kind == yamlv3.ScalarNode && other.Value == value.Value {
+			return strconv.Itoa(idx)
+		}
+	}
+
+	return escapeGoPatch(field) + "=" + escapeGoPatch(value.Value)
+}
+
+func escapeGoPatch(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
@@ -117,12 +117,12 @@ func (report *HumanReport) WriteReport(out io.Writer) error {
 // generateHumanDiffOutput creates a human readable report of the provided diff and writes this into the given bytes buffer. There is an optional flag to indicate whether the document index (which documents of the input file) should be included in the report of the path of the difference.
 func (report *HumanReport) generateHumanDiffOutput(output stringWriter, diff Diff, useGoPatchPaths bool, showPathRoot bool) error {
 	_, _ = output.WriteString("\n")
-	_, _ = output.WriteString(pathToString(diff.Path, useGoPatchPaths, showPathRoot))
+	_, _ = output.WriteString(pathToString(diff.Path, diff.embedding(), useGoPatchPaths, showPathRoot))
 	_, _ = output.WriteString("\n")
 
 	blocks := make([]string, len(diff.Details))
 	for i, detail := range diff.Details {
-		generatedOutput, err := report.generateHumanDetailOutput(detail)
+		generatedOutput, err := report.generateHumanDetailOutput(diff.Path, detail)
 		if err != nil {
 			return err
 		}
@@ -142,7 +142,7 @@ func (report *HumanReport) generateHumanDiffOutput(output stringWriter, diff Dif
 }
 
 // generateHumanDetailOutput only serves as a dispatcher to call the correct sub function for the respective type of change
-func (report *HumanReport) generateHumanDetailOutput(detail Detail) (string, error) {
+func (report *HumanReport) generateHumanDetailOutput(path *ytbx.Path, detail Detail) (string, error) {
 	switch detail.Kind {
 	case ADDITION:
 		return report.generateHumanDetailOutputAddition(detail)
@@ -155,6 +155,18 @@ func (report *HumanReport) generateHumanDetailOutput(detail Detail) (string, err
 
 	case ORDERCHANGE:
 		return report.generateHumanDetailOutputOrderchange(detail)
+
+	case RENAME:
+		return report.generateHumanDetailOutputRename(detail)
+
+	case MOVE:
+		return report.generateHumanDetailOutputMove(path, detail)
+
+	case COMMENT:
+		return report.generateHumanDetailOutputComment(detail)
+
+	case ANCHOR:
+		return report.generateHumanDetailOutputAnchor(detail)
 	}
 
 	return "", fmt.Errorf("unsupported detail type %c", detail.Kind)
@@ -230,6 +242,34 @@ func (report *HumanReport) generateHumanDetailOutputModification(detail Detail)
 	toType := humanReadableType(detail.To)
 
 	switch {
+	case detail.Note == equivalentValueNote:
+		_, _ = output.WriteString(yellow("%c format only change (%s)\n",
+			MODIFICATION,
+			detail.Note,
+		))
+
+		from, err := yamlString(detail.From)
+		if err != nil {
+			return "", err
+		}
+
+		to, err := yamlString(detail.To)
+		if err != nil {
+			return "", err
+		}
+
+		_, _ = output.WriteString(red("%s", createStringWithPrefix("- ", strings.TrimRight(from, "\n"), report.Indent)))
+		_, _ = output.WriteString(green("%s", createStringWithPrefix("+ ", strings.TrimRight(to, "\n"), report.Indent)))
+
+	case detail.Note != "":
+		_, _ = output.WriteString(yellow("%c value change (%s)\n",
+			MODIFICATION,
+			detail.Note,
+		))
+
+		_, _ = output.WriteString(red("%s", createStringWithPrefix("- ", detail.From.Value, report.Indent)))
+		_, _ = output.WriteString(green("%s", createStringWithPrefix("+ ", detail.To.Value, report.Indent)))
+
 	case fromType == "string" && toType == "string":
 		// delegate to special string output
 		report.writeStringDiff(
@@ -343,6 +383,69 @@ func (report *HumanReport) generateHumanDetailOutputOrderchange(detail Detail) (
 	return output.String(), nil
 }
 
+func (report *HumanReport) generateHumanDetailOutputRename(detail Detail) (string, error) {
+	var output bytes.Buffer
+
+	_, _ = output.WriteString(yellow("%c renamed\n", RENAME))
+	_, _ = output.WriteString(red("%s", createStringWithPrefix("- ", detail.From.Value, report.Indent)))
+	_, _ = output.WriteString(green("%s", createStringWithPrefix("+ ", detail.To.Value, report.Indent)))
+
+	return output.String(), nil
+}
+
+func (report *HumanReport) generateHumanDetailOutputMove(path *ytbx.Path, detail Detail) (string, error) {
+	plainPath := func(path *ytbx.Path) string {
+		if report.UseGoPatchPaths {
+			return path.ToGoPatchStyle()
+		}
+
+		return path.ToDotStyle()
+	}
+
+	return yellow("%c moved from %s to %s\n", MOVE, plainPath(detail.Source), plainPath(path)), nil
+}
+
+func (report *HumanReport) generateHumanDetailOutputComment(detail Detail) (string, error) {
+	var output bytes.Buffer
+
+	switch {
+	case detail.From == nil:
+		_, _ = output.WriteString(yellow("%c %s added\n", COMMENT, detail.Note))
+
+	case detail.To == nil:
+		_, _ = output.WriteString(yellow("%c %s removed\n", COMMENT, detail.Note))
+
+	default:
+		_, _ = output.WriteString(yellow("%c %s changed\n", COMMENT, detail.Note))
+	}
+
+	if detail.From != nil {
+		_, _ = output.WriteString(red("%s", createStringWithPrefix("- ", detail.From.Value, report.Indent)))
+	}
+
+	if detail.To != nil {
+		_, _ = output.WriteString(green("%s", createStringWithPrefix("+ ", detail.To.Value, report.Indent)))
+	}
+
+	return output.String(), nil
+}
+
+func (report *HumanReport) generateHumanDetailOutputAnchor(detail Detail) (string, error) {
+	var output bytes.Buffer
+
+	_, _ = output.WriteString(yellow("%c anchor/alias change\n", ANCHOR))
+
+	if detail.From != nil {
+		_, _ = output.WriteString(red("%s", createStringWithPrefix("- ", detail.From.Value, report.Indent)))
+	}
+
+	if detail.To != nil {
+		_, _ = output.WriteString(green("%s", createStringWithPrefix("+ ", detail.To.Value, report.Indent)))
+	}
+
+	return output.String(), nil
+}
+
 func (report *HumanReport) writeStringDiff(output stringWriter, from string, to string) {
 	fromCertText, toCertText, err := report.LoadX509Certs(from, to)
 
@@ -463,6 +566,11 @@ func (report *HumanReport) highlightByLine(from, to string) string {
 }
 
 func humanReadableType(node *yamlv3.Node) string {
+	// custom tags (e.g. `!Ref`) are more descriptive than the kind of the node
+	if node.Kind != yamlv3.DocumentNode && isCustomTag(node.Tag) {
+		return node.Tag
+	}
+
 	switch node.Kind {
 	case yamlv3.DocumentNode:
 		return "document"
@@ -483,7 +591,7 @@ func humanReadableType(node *yamlv3.Node) string {
 
 		default:
 			// use the YAML tag name without the exclamation marks
-			return node.Tag[2:]
+			return strings.TrimPrefix(node.Tag, "!!")
 		}
 
 	case yamlv3.AliasNode:
@@ -615,6 +723,20 @@ func yamlString(input interface{}) (string, error) {
 		if node.Tag == "!!null" {
 			return "<nil>", nil
 		}
+
+		// custom tags are not part of the output, therefore add them in front
+		if isCustomTag(node.Tag) {
+			output, err := neat.NewOutputProcessor(false, true, nil).ToYAML(node)
+			if err != nil {
+				return "", err
+			}
+
+			if node.Kind == yamlv3.ScalarNode {
+				return node.Tag + " " + output, nil
+			}
+
+			return node.Tag + "\n" + output, nil
+		}
 	}
 
 	return neat.NewOutputProcessor(false, true, nil).ToYAML(input)
@@ -768,7 +890,11 @@ func CreateTableStyleString(separator string, indent int, columns ...string) str
 	return buf.String()
 }
 
-func styledGoPatchPath(path *ytbx.Path) string {
+// embeddedPathSeparator separates the path of a string that contains an embedded
+// document from the path inside of the embedded document
+const embeddedPathSeparator = " ⟶ "
+
+func styledGoPatchPath(path *ytbx.Path, embedding *ytbx.Path) string {
 	if path == nil {
 		return bunt.Sprintf("*(file level)*")
 	}
@@ -779,7 +905,11 @@ func styledGoPatchPath(path *ytbx.Path) string {
 
 	sections := []string{""}
 
-	for _, element := range path.PathElements {
+	for i, element := range path.PathElements {
+		if embedding != nil && i == len(embedding.PathElements) && i > 0 {
+			return strings.Join(sections, "/") + embeddedPathSeparator + styledGoPatchPath(&ytbx.Path{PathElements: path.PathElements[i:]}, nil)
+		}
+
 		switch {
 		case element.Name != "" && element.Key == "":
 			sections = append(sections, bunt.Sprintf("*%s*", element.Name))
@@ -795,7 +925,7 @@ func styledGoPatchPath(path *ytbx.Path) string {
 	return strings.Join(sections, "/")
 }
 
-func styledDotStylePath(path *ytbx.Path) string {
+func styledDotStylePath(path *ytbx.Path, embedding *ytbx.Path) string {
 	if path == nil {
 		return bunt.Sprintf("*(file level)*")
 	}
@@ -806,7 +936,11 @@ func styledDotStylePath(path *ytbx.Path) string {
 
 	sections := []string{}
 
-	for _, element := range path.PathElements {
+	for i, element := range path.PathElements {
+		if embedding != nil && i == len(embedding.PathElements) && i > 0 {
+			return strings.Join(sections, ".") + embeddedPathSeparator + styledDotStylePath(&ytbx.Path{PathElements: path.PathElements[i:]}, nil)
+		}
+
 		switch {
 		case element.Key == "" && element.Name != "":
 			sections = append(sections, bunt.Sprintf("*%s*", element.Name))
@@ -21,6 +21,7 @@
 package dyff_test
 
 import (
+	"bytes"
 	"fmt"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -62,10 +63,106 @@ some.yaml.structure.int
     - 12
     + 147
 
+`))
+		})
+
+		It("should show the delta of a Kubernetes quantity change", func() {
+			content := singleDiff("/resources/limits/memory", dyff.MODIFICATION, "1Gi", "1280Mi")
+			content.Details[0].Note = "+256Mi"
+			Expect(humanDiff(content)).To(BeEquivalentTo(`
+resources.limits.memory
+  ± value change (+256Mi)
+    - 1Gi
+    + 1280Mi
+
+`))
+		})
+
+		It("should show that a value was only reformatted if compared by value", func() {
+			result, err := compare(yml(`{"value": 0x10}`), yml(`{"value": 16}`), dyff.SemanticScalars(true))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(HaveLen(1))
+			Expect(humanDiff(result[0])).To(BeEquivalentTo(`
+value
+  ± format only change (equivalent value)
+    - 0x10
+    + 16
+
+`))
+
+			result, err = compare(yml(`{"value": 0x10}`), yml(`{"value": 16}`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(HaveLen(1))
+			Expect(humanDiff(result[0])).To(BeEquivalentTo(`
+value
+  ± value change
+    - 0x10
+    + 16
+
+`))
+		})
+
+		It("should separate the path of an embedded document from the path of the string", func() {
+			result, err := compare(
+				yml(`{"data": {"config.json": "{\"b\": {\"c\": 1}}"}}`),
+				yml(`{"data": {"config.json": "{\"b\": {\"c\": 2}}"}}`),
+				dyff.DetectEmbeddedDocuments(true),
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(HaveLen(1))
+			Expect(humanDiff(result[0])).To(BeEquivalentTo(`
+data.config.json ⟶ b.c
+  ± value change
+    - 1
+    + 2
+
+`))
+
+			reporter := dyff.HumanReport{
+				Report:          dyff.Report{Diffs: result},
+				Indent:          2,
+				OmitHeader:      true,
+				UseGoPatchPaths: true,
+			}
+
+			var buf bytes.Buffer
+			Expect(reporter.WriteReport(&buf)).To(Succeed())
+			Expect(buf.String()).To(HavePrefix("\n/data/config.json ⟶ /b/c\n"))
+		})
+
+		It("should show a rename", func() {
+			content := singleDiff("/spec/containers/name=app", dyff.RENAME, "app", "web")
+			Expect(humanDiff(content)).To(BeEquivalentTo(`
+spec.containers.app
+  → renamed
+    - app
+    + web
+
+`))
+		})
+
+		It("should show custom tags", func() {
+			result, err := compare(yml(`{"bucket": !Ref Bucket, "arn": !Sub "arn:${Partition}"}`), yml(`{"bucket": !Ref Logs, "arn": stack-logs}`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(HaveLen(2))
+			Expect(humanDiff(result[0])).To(BeEquivalentTo(`
+bucket
+  ± value change
+    - !Ref Bucket
+    + !Ref Logs
+
+`))
+			Expect(humanDiff(result[1])).To(BeEquivalentTo(`
+arn
+  ± type change from !Sub to string
+    - !Sub arn:${Partition}
+    + stack-logs
+
 `))
 		})
 
 		It("should show a type difference", func() {
+
 			content := singleDiff("/some/yaml/structure/test", dyff.MODIFICATION, 12, 12.0)
 			Expect(humanDiff(content)).To(BeEquivalentTo(`
 some.yaml.structure.test
@@ -0,0 +1,106 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gonvenience/bunt"
+	"github.com/gonvenience/text"
+	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// ConflictReport is a reporter with human readable output of the conflicts of
+// a three-way merge, where the values of both sides are shown next to each other
+type ConflictReport struct {
+	MergeResult
+	Indent          int
+	NoTableStyle    bool
+	OmitHeader      bool
+	UseGoPatchPaths bool
+}
+
+// WriteReport writes the conflicts in human readable form to the provided writer
+func (report *ConflictReport) WriteReport(out io.Writer) error {
+	writer := bufio.NewWriter(out)
+	defer writer.Flush()
+
+	conflicts := report.Conflicts()
+
+	// Only show the document index if there is more than one document to show
+	showPathRoot := len(report.Base.Documents) > 1
+
+	if !report.OmitHeader {
+		_, _ = fmt.Fprintf(writer, "%s detected merging %s and %s based on %s\n",
+			bunt.Style(text.Plural(len(conflicts), "conflict"), bunt.Bold()),
+			ytbx.HumanReadableLocationInformation(report.Ours),
+			ytbx.HumanReadableLocationInformation(report.Theirs),
+			ytbx.HumanReadableLocationInformation(report.Base),
+		)
+	}
+
+	human := &HumanReport{NoTableStyle: report.NoTableStyle}
+	for _, conflict := range conflicts {
+		ours, err := conflictSide("ours", conflict.Ours)
+		if err != nil {
+			return err
+		}
+
+		theirs, err := conflictSide("theirs", conflict.Theirs)
+		if err != nil {
+			return err
+		}
+
+		var sides bytes.Buffer
+		human.writeTextBlocks(&sides, 0, ours, theirs)
+
+		var output bytes.Buffer
+		_, _ = output.WriteString(yellow("%c conflicting changes\n", MODIFICATION))
+		_, _ = output.WriteString(createStringWithPrefix("", strings.TrimRight(sides.String(), "\n"), report.Indent))
+
+		_, _ = writer.WriteString("\n")
+		_, _ = writer.WriteString(pathToString(conflict.Path, nil, report.UseGoPatchPaths, showPathRoot))
+		_, _ = writer.WriteString("\n")
+		_, _ = writer.WriteString(createStringWithPrefix("", strings.TrimRight(output.String(), "\n"), report.Indent))
+	}
+
+	// Finish with one last newline so that we do not end next to the prompt
+	_, _ = writer.WriteString("\n")
+	return nil
+}
+
+func conflictSide(side string, node *yamlv3.Node) (string, error) {
+	if node == nil {
+		return bold("%s", side) + "\n" + dimgray("(removed)"), nil
+	}
+
+	output, err := yamlString(node)
+	if err != nil {
+		return "", err
+	}
+
+	return bold("%s", side) + "\n" + strings.TrimRight(output, "\n"), nil
+}
@@ -0,0 +1,865 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// JSONPatchReport writes the differences as a JSON Patch (RFC 6902), which
+// transforms the `from` document into the `to` document. In case of input
+// files with multiple documents, there is one patch per document (one line
+// each) in the order of the documents. Documents that were added, removed, or
+// reordered cannot be expressed as a patch, which results in an error.
+type JSONPatchReport struct {
+	Report
+}
+
+// MergePatchReport writes the differences as a JSON Merge Patch (RFC 7386),
+// which transforms the `from` document into the `to` document. In case of
+// input files with multiple documents, there is one patch per document (one
+// line each) in the order of the documents. Lists are always replaced as a
+// whole and null values cannot be expressed, which is a limitation of the
+// format.
+type MergePatchReport struct {
+	Report
+}
+
+// WriteReport writes the JSON Patch of each document to the writer
+func (report *JSONPatchReport) WriteReport(out io.Writer) error {
+	patches, err := report.JSONPatches()
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(out)
+	defer writer.Flush()
+
+	for _, operations := range patches {
+		if operations == nil {
+			operations = []JSONPatchOperation{}
+		}
+
+		data, err := json.Marshal(operations)
+		if err != nil {
+			return err
+		}
+
+		_, _ = writer.Write(data)
+		_, _ = writer.WriteString("\n")
+	}
+
+	return nil
+}
+
+// WriteReport writes the JSON Merge Patch of each document to the writer
+func (report *MergePatchReport) WriteReport(out io.Writer) error {
+	patches, err := report.MergePatches()
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(out)
+	defer writer.Flush()
+
+	for _, patch := range patches {
+		var buf bytes.Buffer
+		if err := writeJSON(&buf, patch); err != nil {
+			return err
+		}
+
+		_, _ = writer.Write(buf.Bytes())
+		_, _ = writer.WriteString("\n")
+	}
+
+	return nil
+}
+
+// JSONPatches returns the JSON Patch operations for each document of the
+// `from` input file, which transform the document into its counterpart
+func (report Report) JSONPatches() ([][]JSONPatchOperation, error) {
+	result := make([][]JSONPatchOperation, len(report.From.Documents))
+	for idx := range report.From.Documents {
+		generator, err := report.patchGenerator(idx)
+		if err != nil {
+			return nil, err
+		}
+
+		result[idx] = generator.operations
+	}
+
+	return result, nil
+}
+
+// MergePatches returns the JSON Merge Patch for each document of the `from`
+// input file, which transforms the document into its counterpart
+func (report Report) MergePatches() ([]*yamlv3.Node, error) {
+	result := make([]*yamlv3.Node, len(report.From.Documents))
+	for idx := range report.From.Documents {
+		generator, err := report.patchGenerator(idx)
+		if err != nil {
+			return nil, err
+		}
+
+		result[idx] = mergePatch(documentContent(report.From.Documents[idx]), generator.root)
+	}
+
+	return result, nil
+}
+
+// patchGenerator creates the patch operations for the differences of the
+// document with the given index. The operations are applied to a working copy
+// of the document one after another, so that named list entries and list
+// indices always refer to the current state of the document.
+type patchGenerator struct {
+	root       *yamlv3.Node
+	target     *yamlv3.Node
+	copies     map[*yamlv3.Node]*yamlv3.Node
+	offsets    map[*yamlv3.Node]int
+	renames    map[*yamlv3.Node]map[string]string
+	lists      []listTarget
+	operations []JSONPatchOperation
+}
+
+// listTarget is a list of the working copy, which needs to end up with the
+// order of the respective list in the target document
+type listTarget struct {
+	list   *yamlv3.Node
+	target *yamlv3.Node
+}
+
+func (report Report) patchGenerator(idx int) (*patchGenerator, error) {
+	generator := &patchGenerator{
+		copies:  map[*yamlv3.Node]*yamlv3.Node{},
+		offsets: map[*yamlv3.Node]int{},
+		renames: map[*yamlv3.Node]map[string]string{},
+	}
+
+	generator.root = copyNode(documentContent(report.From.Documents[idx]), generator.copies)
+	generator.target = documentContent(report.targetDocument(idx))
+
+	for _, diff := range report.Diffs {
+		// a patch transforms a document into its counterpart, it cannot add,
+		// remove, or reorder documents
+		if diff.Path == nil || isDocumentChange(diff) {
+			return nil, fmt.Errorf("document-level changes (added, removed, or reordered documents) cannot be expressed as a patch")
+		}
+
+		if diff.Path.DocumentIdx != idx {
+			continue
+		}
+
+		for _, detail := range diff.Details {
+			if err := generator.detail(*diff.Path, detail); err != nil {
+				return nil, fmt.Errorf("failed to create patch for %s: %w", diff.Path.String(), err)
+			}
+		}
+	}
+
+	if err := generator.orderLists(); err != nil {
+		return nil, err
+	}
+
+	return generator, nil
+}
+
+// targetDocument returns the document of the `to` input file that was
+// compared with the document of the `from` input file with the given index
+func (report Report) targetDocument(idx int) *yamlv3.Node {
+	if counterparts := report.documentCounterparts(); idx < len(counterparts) && counterparts[idx] >= 0 {
+		return report.To.Documents[counterparts[idx]]
+	}
+
+	return nil
+}
+
+// isDocumentChange returns whether the difference is an added or removed
+// document, which has no counterpart that a patch could apply to
+func isDocumentChange(diff Diff) bool {
+	if len(diff.Path.PathElements) > 0 {
+		return false
+	}
+
+	for _, detail := range diff.Details {
+		for _, node := range []*yamlv3.Node{detail.From, detail.To} {
+			if node != nil && node.Kind == yamlv3.DocumentNode {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func documentContent(node *yamlv3.Node) *yamlv3.Node {
+	if node != nil && node.Kind == yamlv3.DocumentNode {
+		if len(node.Content) == 0 {
+			return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!null", Value: "null"}
+		}
+
+		return node.Content[0]
+	}
+
+	return node
+}
+
+func (generator *patchGenerator) apply(operation JSONPatchOperation) error {
+	if err := applyOperation(&generator.root, operation); err != nil {
+		return err
+	}
+
+	generator.operations = append(generator.operations, operation)
+	return nil
+}
+
+func (generator *patchGenerator) detail(path ytbx.Path, detail Detail) error {
+	elements := path.PathElements
+
+	// a patch cannot refer to the content of a string, the string containing
+	// the embedded document is replaced as a whole instead
+	if detail.Embedding != nil {
+		return generator.embedding(detail.Embedding.PathElements)
+	}
+
+	switch detail.Kind {
+	case MODIFICATION:
+		// values that are only written differently need no patch operation
+		if isEquivalentValue(detail) {
+			return nil
+		}
+
+		if len(elements) == 0 && (detail.From == nil || detail.To == nil || detail.From.Kind == yamlv3.DocumentNode || detail.To.Kind == yamlv3.DocumentNode) {
+			return fmt.Errorf("documents that were added or removed cannot be expressed as a patch")
+		}
+
+		tokens, _, err := generator.resolve(elements)
+		if err != nil {
+			return err
+		}
+
+		// entries of lists without identifier that were paired by similarity
+		// can be modified and end up at a different position at the same time
+		generator.orderIndexedLists(elements)
+
+		if detail.To == nil {
+			return generator.apply(JSONPatchOperation{Op: "remove", Path: pointer(tokens)})
+		}
+
+		op := "replace"
+		if detail.From == nil {
+			op = "add"
+		}
+
+		return generator.apply(JSONPatchOperation{Op: op, Path: pointer(tokens), Value: detail.To})
+
+	case ADDITION, REMOVAL:
+		entries := detail.To
+		if detail.Kind == REMOVAL {
+			entries = detail.From
+		}
+
+		if entries == nil || entries.Kind == yamlv3.DocumentNode {
+			return fmt.Errorf("documents that were added or removed cannot be expressed as a patch")
+		}
+
+		if positional, idx := isPositional(elements); positional {
+			tokens, list, err := generator.resolve(elements[:len(elements)-1])
+			if err == nil && list.Kind == yamlv3.SequenceNode {
+				return generator.positionalEntries(tokens, list, idx, detail.Kind, entries)
+			}
+		}
+
+		tokens, node, err := generator.resolve(elements)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case node.Kind == yamlv3.MappingNode && entries.Kind == yamlv3.MappingNode:
+			for i := 0; i+1 < len(entries.Content); i += 2 {
+				operation := JSONPatchOperation{Op: "add", Path: pointer(append(tokens, entries.Content[i].Value)), Value: entries.Content[i+1]}
+				if detail.Kind == REMOVAL {
+					operation = JSONPatchOperation{Op: "remove", Path: pointer(append(tokens, entries.Content[i].Value))}
+				}
+
+				if err := generator.apply(operation); err != nil {
+					return err
+				}
+			}
+
+			return nil
+
+		case node.Kind == yamlv3.SequenceNode && entries.Kind == yamlv3.SequenceNode:
+			generator.orderList(node, elements)
+			if detail.Kind == REMOVAL {
+				return generator.removeEntries(tokens, node, entries)
+			}
+
+			return generator.addEntries(tokens, node, elements, entries)
+		}
+
+		return fmt.Errorf("unsupported %s of %s", kindName(detail.Kind), humanReadableType(entries))
+
+	case ORDERCHANGE:
+		_, node, err := generator.resolve(elements)
+		if err != nil {
+			return err
+		}
+
+		// the order of keys in JSON objects is irrelevant
+		if node.Kind == yamlv3.SequenceNode {
+			generator.orderList(node, elements)
+		}
+
+		return nil
+
+	case RENAME:
+		return generator.rename(elements, detail)
+
+	case MOVE:
+		if detail.Source == nil || len(elements) == 0 {
+			return fmt.Errorf("move without source")
+		}
+
+		source, _, err := generator.resolve(detail.Source.PathElements)
+		if err != nil {
+			return err
+		}
+
+		parent, _, err := generator.resolve(elements[:len(elements)-1])
+		if err != nil {
+			return err
+		}
+
+		return generator.apply(JSONPatchOperation{
+			Op:   "move",
+			From: pointer(source),
+			Path: pointer(append(parent, elements[len(elements)-1].Name)),
+		})
+	}
+
+	// comments and anchors have no representation in JSON
+	return nil
+}
+
+// embedding replaces the string that contains an embedded document with the
+// respective string of the target document, unless it was replaced already
+func (generator *patchGenerator) embedding(elements []ytbx.PathElement) error {
+	tokens, node, target, err := generator.walk(elements)
+	if err != nil {
+		return err
+	}
+
+	if target == nil {
+		return fmt.Errorf("failed to find the string that contains the embedded document")
+	}
+
+	if node.Value == target.Value {
+		return nil
+	}
+
+	return generator.apply(JSONPatchOperation{Op: "replace", Path: pointer(tokens), Value: target})
+}
+
+func kindName(kind rune) string {
+	if kind == REMOVAL {
+		return "removal"
+	}
+
+	return "addition"
+}
+
+// isPositional returns whether the last path element refers to a list entry
+// by its index, which is the case for lists that are compared by position
+func isPositional(elements []ytbx.PathElement) (bool, int) {
+	if len(elements) == 0 {
+		return false, 0
+	}
+
+	last := elements[len(elements)-1]
+	return last.Key == "" && last.Name == "" && last.Idx >= 0, last.Idx
+}
+
+// positionalEntries adds or removes entries of a list that is compared by
+// position, removals refer to the index in the old list and additions to the
+// index in the new list
+func (generator *patchGenerator) positionalEntries(tokens []string, list *yamlv3.Node, idx int, kind rune, entries *yamlv3.Node) error {
+	position := idx + generator.offsets[list]
+	for i, entry := range entries.Content {
+		if kind == REMOVAL {
+			if err := generator.apply(JSONPatchOperation{Op: "remove", Path: pointer(append(tokens, strconv.Itoa(position)))}); err != nil {
+				return err
+			}
+
+			generator.offsets[list]--
+			continue
+		}
+
+		if err := generator.apply(JSONPatchOperation{Op: "add", Path: pointer(append(tokens, strconv.Itoa(idx+i))), Value: entry}); err != nil {
+			return err
+		}
+
+		generator.offsets[list]++
+	}
+
+	return nil
+}
+
+func (generator *patchGenerator) removeEntries(tokens []string, list *yamlv3.Node, entries *yamlv3.Node) error {
+	for _, entry := range entries.Content {
+		position := -1
+		for i, current := range list.Content {
+			if current == generator.copies[entry] {
+				position = i
+				break
+			}
+		}
+
+		if position < 0 {
+			for i, current := range list.Content {
+				if equalJSON(current, entry) {
+					position = i
+					break
+				}
+			}
+		}
+
+		if position < 0 {
+			return fmt.Errorf("failed to find removed list entry")
+		}
+
+		if err := generator.apply(JSONPatchOperation{Op: "remove", Path: pointer(append(tokens, strconv.Itoa(position)))}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addEntries inserts the entries at their position in the target list, or
+// appends them in case the target list is not known
+func (generator *patchGenerator) addEntries(tokens []string, list *yamlv3.Node, elements []ytbx.PathElement, entries *yamlv3.Node) error {
+	target := generator.counterpart(elements)
+
+	for _, entry := range entries.Content {
+		position := len(list.Content)
+		if target != nil && target.Kind == yamlv3.SequenceNode {
+			for i, candidate := range target.Content {
+				if candidate == entry {
+					position = min(i, len(list.Content))
+					break
+				}
+			}
+		}
+
+		if err := generator.apply(JSONPatchOperation{Op: "add", Path: pointer(append(tokens, strconv.Itoa(position))), Value: entry}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (generator *patchGenerator) rename(elements []ytbx.PathElement, detail Detail) error {
+	if len(elements) == 0 || detail.From == nil || detail.To == nil {
+		return fmt.Errorf("rename without name")
+	}
+
+	parentTokens, parent, err := generator.resolve(elements[:len(elements)-1])
+	if err != nil {
+		return err
+	}
+
+	last := elements[len(elements)-1]
+	oldName, newName := detail.From.Value, detail.To.Value
+
+	if generator.renames[parent] == nil {
+		generator.renames[parent] = map[string]string{}
+	}
+
+	switch parent.Kind {
+	case yamlv3.MappingNode:
+		generator.renames[parent][oldName] = newName
+		return generator.apply(JSONPatchOperation{
+			Op:   "move",
+			From: pointer(append(parentTokens, oldName)),
+			Path: pointer(append(parentTokens, newName)),
+		})
+
+	case yamlv3.SequenceNode:
+		tokens, _, err := generator.resolve(elements)
+		if err != nil {
+			return err
+		}
+
+		fields := map[string]string{}
+		switch {
+		case last.Key == k8sItem.String():
+			return fmt.Errorf("renames of Kubernetes resources are not supported")
+
+		case last.Key != "":
+			fields[last.Key] = newName
+
+		default:
+			for field, value := range compositeFields(newName) {
+				fields[field] = value
+			}
+		}
+
+		for field, value := range fields {
+			fieldTokens := append(append([]string{}, tokens...), strings.Split(field, ".")...)
+			if err := generator.apply(JSONPatchOperation{
+				Op:    "replace",
+				Path:  pointer(fieldTokens),
+				Value: &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: value},
+			}); err != nil {
+				return err
+			}
+		}
+
+		generator.renames[parent][oldName] = newName
+		generator.orderList(parent, elements[:len(elements)-1])
+		return nil
+	}
+
+	return fmt.Errorf("unsupported rename")
+}
+
+// compositeFields parses the name of an entry of a list with a composite
+// identifier, e.g. `containerPort=80,protocol=TCP`
+func compositeFields(name string) map[string]string {
+	result := map[string]string{}
+	for _, part := range strings.Split(name, ",") {
+		if field, value, ok := strings.Cut(part, "="); ok {
+			result[field] = value
+		}
+	}
+
+	return result
+}
+
+// resolve translates the path elements into JSON pointer tokens using the
+// current state of the working copy
+func (generator *patchGenerator) resolve(elements []ytbx.PathElement) ([]string, *yamlv3.Node, error) {
+	tokens, node, _, err := generator.walk(elements)
+	return tokens, node, err
+}
+
+// counterpart returns the node in the target document that corresponds to the
+// node at the path in the working copy, or nil if there is none
+func (generator *patchGenerator) counterpart(elements []ytbx.PathElement) *yamlv3.Node {
+	_, _, target, err := generator.walk(elements)
+	if err != nil {
+		return nil
+	}
+
+	return target
+}
+
+// walk follows the path elements in the working copy and in the target
+// document at the same time, names of renamed keys and list entries are
+// translated to their new names
+func (generator *patchGenerator) walk(elements []ytbx.PathElement) ([]string, *yamlv3.Node, *yamlv3.Node, error) {
+	node, target := generator.root, generator.target
+	tokens := []string{}
+
+	for _, element := range elements {
+		name := element.Name
+		if renamed, ok := generator.renames[node][name]; ok {
+			name = renamed
+		}
+
+		if target != nil {
+			target = followAlias(target)
+		}
+
+		switch node.Kind {
+		case yamlv3.MappingNode:
+			idx := mappingKeyIndex(node, name)
+			if idx < 0 {
+				return nil, nil, nil, fmt.Errorf("failed to find %q in the document", name)
+			}
+
+			tokens = append(tokens, name)
+			node = node.Content[idx+1]
+
+			if target != nil && target.Kind == yamlv3.MappingNode {
+				if idx := mappingKeyIndex(target, name); idx >= 0 {
+					target = target.Content[idx+1]
+				} else {
+					target = nil
+				}
+			} else {
+				target = nil
+			}
+
+		case yamlv3.SequenceNode:
+			named := element.Key != "" || element.Name != ""
+
+			idx := -1
+			switch {
+			case named:
+				idx = findNamedEntry(node, element.Key, name)
+
+			default:
+				if position := element.Idx + generator.offsets[node]; position >= 0 && position < len(node.Content) {
+					idx = position
+				}
+			}
+
+			if idx < 0 {
+				return nil, nil, nil, fmt.Errorf("failed to find list entry %q in the document", name)
+			}
+
+			tokens = append(tokens, strconv.Itoa(idx))
+			node = node.Content[idx]
+
+			if target != nil && target.Kind == yamlv3.SequenceNode {
+				if named {
+					idx = findNamedEntry(target, element.Key, name)
+				}
+
+				if idx >= 0 && idx < len(target.Content) {
+					target = target.Content[idx]
+				} else {
+					target = nil
+				}
+			} else {
+				target = nil
+			}
+
+		default:
+			return nil, nil, nil, fmt.Errorf("failed to find %q in the document", name)
+		}
+	}
+
+	if target != nil {
+		target = followAlias(target)
+	}
+
+	return tokens, node, target, nil
+}
+
+// findNamedEntry returns the index of the list entry with the given name,
+// where the key is the identifier, or empty for composite identifiers
+func findNamedEntry(list *yamlv3.Node, key string, name string) int {
+	var identifier listItemIdentifier = &singleField{IdentifierFieldName: key}
+	switch key {
+	case k8sItem.String():
+		identifier = k8sItem
+
+	case "":
+		fields := compositeFields(name)
+		for i, entry := range list.Content {
+			matches := len(fields) > 0
+			for field, value := range fields {
+				if node, err := grab(followAlias(entry), field); err != nil || followAlias(node).Value != value {
+					matches = false
+					break
+				}
+			}
+
+			if matches {
+				return i
+			}
+		}
+
+		return -1
+	}
+
+	for i, entry := range list.Content {
+		if entryName, err := identifier.Name(followAlias(entry)); err == nil && entryName == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// orderList registers the list to be sorted like the respective list in the
+// target document once all other changes are applied
+func (generator *patchGenerator) orderList(list *yamlv3.Node, elements []ytbx.PathElement) {
+	target := generator.counterpart(elements)
+	if target == nil || target.Kind != yamlv3.SequenceNode {
+		return
+	}
+
+	for _, entry := range generator.lists {
+		if entry.list == list {
+			return
+		}
+	}
+
+	generator.lists = append(generator.lists, listTarget{list: list, target: target})
+}
+
+// orderIndexedLists registers all lists on the path that are accessed by the
+// index of an entry to be sorted like the respective list in the target document
+func (generator *patchGenerator) orderIndexedLists(elements []ytbx.PathElement) {
+	for i := range elements {
+		if positional, _ := isPositional(elements[:i+1]); !positional {
+			continue
+		}
+
+		if _, list, err := generator.resolve(elements[:i]); err == nil && list.Kind == yamlv3.SequenceNode {
+			generator.orderList(list, elements[:i])
+		}
+	}
+}
+
+// orderLists moves the entries of the registered lists so that they have the
+// same order as the entries of the respective list in the target document,
+// entries are matched by their content, unmatched entries end up last
+func (generator *patchGenerator) orderLists() error {
+	hasher := &compare{ctx: context.Background(), hashes: newNodeHashes()}
+
+	// sort nested lists first so that the entries of the outer lists already
+	// match their target entries when the outer lists are sorted
+	depths := map[*yamlv3.Node]int{}
+	for _, entry := range generator.lists {
+		tokens, _ := generator.pointerOf(entry.list)
+		depths[entry.list] = len(tokens)
+	}
+
+	sort.SliceStable(generator.lists, func(i, j int) bool {
+		return depths[generator.lists[i].list] > depths[generator.lists[j].list]
+	})
+
+	for _, entry := range generator.lists {
+		tokens, ok := generator.pointerOf(entry.list)
+		if !ok {
+			continue
+		}
+
+		list := entry.list
+		used := make([]bool, len(list.Content))
+		var order []*yamlv3.Node
+		for _, targetEntry := range entry.target.Content {
+			hash := hasher.calcNodeHash(ytbx.Path{}, targetEntry)
+			for i, current := range list.Content {
+				if !used[i] && hasher.calcNodeHash(ytbx.Path{}, current) == hash {
+					used[i] = true
+					order = append(order, current)
+					break
+				}
+			}
+		}
+
+		for position, wanted := range order {
+			current := -1
+			for i, node := range list.Content {
+				if node == wanted {
+					current = i
+					break
+				}
+			}
+
+			if current == position {
+				continue
+			}
+
+			if err := generator.apply(JSONPatchOperation{
+				Op:   "move",
+				From: pointer(append(tokens, strconv.Itoa(current))),
+				Path: pointer(append(tokens, strconv.Itoa(position))),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// pointerOf finds the JSON pointer tokens of the node in the working copy
+func (generator *patchGenerator) pointerOf(needle *yamlv3.Node) ([]string, bool) {
+	var search func(node *yamlv3.Node, tokens []string) ([]string, bool)
+	search = func(node *yamlv3.Node, tokens []string) ([]string, bool) {
+		if node == needle {
+			return tokens, true
+		}
+
+		switch node.Kind {
+		case yamlv3.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if result, ok := search(node.Content[i+1], append(tokens, node.Content[i].Value)); ok {
+					return result, true
+				}
+			}
+
+		case yamlv3.SequenceNode:
+			for i, entry := range node.Content {
+				if result, ok := search(entry, append(tokens, strconv.Itoa(i))); ok {
+					return result, true
+				}
+			}
+		}
+
+		return nil, false
+	}
+
+	return search(generator.root, []string{})
+}
+
+// mergePatch creates a JSON Merge Patch (RFC 7386) that transforms the from
+// node into the to node
+func mergePatch(from *yamlv3.Node, to *yamlv3.Node) *yamlv3.Node {
+	from, to = followAlias(from), followAlias(to)
+	if from.Kind != yamlv3.MappingNode || to.Kind != yamlv3.MappingNode {
+		return to
+	}
+
+	result := &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+	for i := 0; i+1 < len(from.Content); i += 2 {
+		key, value := from.Content[i], followAlias(from.Content[i+1])
+
+		idx := mappingKeyIndex(to, key.Value)
+		switch {
+		case idx < 0:
+			result.Content = append(result.Content, key, &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!null", Value: "null"})
+
+		case equalJSON(value, to.Content[idx+1]):
+			continue
+
+		default:
+			result.Content = append(result.Content, key, mergePatch(value, to.Content[idx+1]))
+		}
+	}
+
+	for i := 0; i+1 < len(to.Content); i += 2 {
+		if mappingKeyIndex(from, to.Content[i].Value) < 0 {
+			result.Content = append(result.Content, to.Content[i], followAlias(to.Content[i+1]))
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,561 @@
+// Copyright © 2026 The Homeport Team
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dyff_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/gonvenience/ytbx"
+	yamlv3 "gopkg.in/yaml.v3"
+
+	"github.com/homeport/dyff/pkg/dyff"
+)
+
+var _ = Describe("patch reports", func() {
+	roundTrip := func(from string, to string, compareOptions ...dyff.CompareOption) []dyff.JSONPatchOperation {
+		fromDoc, toDoc := yml(from), yml(to)
+
+		report, err := dyff.CompareNodes(fromDoc, toDoc, compareOptions...)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(report.Diffs).ToNot(BeEmpty())
+
+		patches, err := report.JSONPatches()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(patches).To(HaveLen(1))
+
+		data, err := json.Marshal(patches[0])
+		Expect(err).ToNot(HaveOccurred())
+
+		patched, err := dyff.ApplyJSONPatch(fromDoc, data)
+		Expect(err).ToNot(HaveOccurred())
+
+		result, err := dyff.CompareNodes(patched, toDoc, compareOptions...)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.Diffs).To(BeEmpty(), "patch %s", string(data))
+
+		return patches[0]
+	}
+
+	ops := func(operations []dyff.JSONPatchOperation) []string {
+		var result []string
+		for _, operation := range operations {
+			result = append(result, operation.Op)
+		}
+
+		return result
+	}
+
+	Context("applying JSON patches", func() {
+		It("should apply all kinds of operations", func() {
+			patched, err := dyff.ApplyJSONPatch(yml(`{"a": {"b": [1, 2, 3]}, "c": "d"}`), []byte(`[
+  {"op": "test", "path": "/c", "value": "d"},
+  {"op": "add", "path": "/a/b/-", "value": 4},
+  {"op": "remove", "path": "/a/b/0"},
+  {"op": "replace", "path": "/c", "value": {"e": "f"}},
+  {"op": "copy", "from": "/c", "path": "/g"},
+  {"op": "move", "from": "/a/b", "path": "/h~1i"}
+]`))
+			Expect(err).ToNot(HaveOccurred())
+
+			result, err := dyff.CompareNodes(patched, yml(`{"a": {}, "c": {"e": "f"}, "g": {"e": "f"}, "h/i": [2, 3, 4]}`))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Diffs).To(BeEmpty())
+		})
+
+		It("should keep anchors and not change anchored content through aliases", func() {
+			document := singleDoc("base: &base {image: foo}\napp: *base\nother: *base\n")
+			patched, err := dyff.ApplyJSONPatch(document, []byte(`[{"op": "replace", "path": "/app/image", "value": "bar"}]`))
+			Expect(err).ToNot(HaveOccurred())
+
+			out, err := yamlv3.Marshal(patched)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(out)).To(Equal("base: &base {image: foo}\napp: {image: bar}\nother: *base\n"))
+		})
+
+		It("should keep the quotes of strings that cannot be written as a literal block", func() {
+			patched, err := dyff.ApplyJSONPatch(singleDoc("a: foo\nb: bar\n"), []byte(`[
+  {"op": "replace", "path": "/a", "value": "\nline1\nline2\n"},
+  {"op": "replace", "path": "/b", "value": "line1\nline2\n"}
+]`))
+			Expect(err).ToNot(HaveOccurred())
+
+			out, err := yamlv3.Marshal(patched)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(out)).To(Equal("a: \"\\nline1\\nline2\\n\"\nb: |\n    line1\n    line2\n"))
+
+			var result map[string]string
+			Expect(yamlv3.Unmarshal(out, &result)).To(Succeed())
+			Expect(result).To(Equal(map[string]string{"a": "\nline1\nline2\n", "b": "line1\nline2\n"}))
+		})
+
+		It("should fail if a test operation does not match", func() {
+			_, err := dyff.ApplyJSONPatch(yml(`{"a": 1}`), []byte(`[{"op": "test", "path": "/a", "value": 2}]`))
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail if the path does not exist", func() {
+			_, err := dyff.ApplyJSONPatch(yml(`{"a": 1}`), []byte(`[{"op": "remove", "path": "/b"}]`))
+			Expect(err).To(HaveOccurred())
+
+			_, err = dyff.ApplyJSONPatch(yml(`{"a": [1]}`), []byte(`[{"op": "add", "path": "/a/2", "value": 2}]`))
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("creating JSON patches", func() {
+		It("should create a patch for modified, added, and removed map entries", func() {
+			Expect(ops(roundTrip(
+				`{"name": "foo", "spec": {"replicas": 1, "paused": true}}`,
+				`{"name": "foo", "spec": {"replicas": 2, "selector": {"app": "foo"}}}`,
+			))).To(ConsistOf("replace", "add", "remove"))
+		})
+
+		It("should resolve named list entries to indices", func() {
+			operations := roundTrip(`
+list:
+- name: one
+  value: 1
+- name: two
+  value: 2
+- name: three
+  value: 3
+`, `
+list:
+- name: one
+  value: 1
+- name: three
+  value: 33
+- name: four
+  value: 4
+`)
+			Expect(operations).To(ContainElement(dyff.JSONPatchOperation{Op: "remove", Path: "/list/1"}))
+			Expect(operations).To(ContainElement(HaveField("Path", "/list/1/value")))
+		})
+
+		It("should turn order changes of named lists into move operations", func() {
+			operations := roundTrip(`
+list:
+- name: one
+- name: two
+- name: three
+`, `
+list:
+- name: three
+- name: one
+- name: two
+`)
+			Expect(ops(operations)).To(HaveEach("move"))
+		})
+
+		It("should create a patch for simple lists", func() {
+			roundTrip(`{"list": [a, b, c, d]}`, `{"list": [d, a, x, c]}`)
+		})
+
+		It("should create a patch for lists compared by position", func() {
+			roundTrip(`{"list": [a, b, c, d, e]}`, `{"list": [a, x, y, c, e, f]}`, dyff.PositionalLists(true))
+			roundTrip(`{"list": [a, b, c, d, e]}`, `{"list": [b, e]}`, dyff.PositionalLists(true))
+		})
+
+		It("should create a patch for renamed list entries and keys", func() {
+			roundTrip(`
+containers:
+- name: main
+  image: foobar:1.0
+  args: [--verbose, --port, "8080"]
+  env: {LEVEL: debug, MODE: fast}
+`, `
+containers:
+- name: app
+  image: foobar:1.1
+  args: [--verbose, --port, "8080"]
+  env: {LEVEL: debug, MODE: fast}
+`, dyff.DetectEntryRenames(true))
+		})
+
+		It("should create a patch for similar list entries that were modified and moved", func() {
+			roundTrip(`
+list:
+- {a: 1, b: 2, c: 3}
+- {x: 1, y: 2, z: 3}
+- keep
+`, `
+list:
+- keep
+- {x: 1, y: 2, z: 4}
+- {a: 1, b: 2, c: 4}
+`, dyff.FuzzyListPairing(0.5))
+
+			roundTrip(`
+list:
+- {a: 1, b: 2, c: 3}
+- {x: 1, y: 2, z: 3}
+- removed
+`, `
+list:
+- {x: 1, y: 2, z: 3}
+- {a: 1, b: 2, c: 4}
+`, dyff.FuzzyListPairing(0.5))
+		})
+
+		It("should create a patch for moved map entries", func() {
+			roundTrip(`
+spec:
+  template:
+    config: {a: 1, b: 2, c: 3, d: 4}
+`, `
+spec:
+  config: {a: 1, b: 2, c: 3, d: 4}
+  template: {}
+`, dyff.DetectMapEntryMoves(true))
+		})
+
+		It("should not create operations for values that are only equivalent", func() {
+			report, err := dyff.CompareNodes(
+				yml(`{"a": 1.0, "b": 1.5, "c": 2}`),
+				yml(`{"a": 1, "b": 1.6, "c": 3}`),
+				dyff.SemanticScalars(true),
+				dyff.FloatTolerance(0.2),
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(report.Diffs).To(HaveLen(3))
+
+			patches, err := report.JSONPatches()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(patches).To(HaveLen(1))
+			Expect(patches[0]).To(HaveLen(1))
+			Expect(patches[0][0].Op).To(Equal("replace"))
+			Expect(patches[0][0].Path).To(Equal("/c"))
+		})
+
+		It("should create patches for renamed documents that changed their position", func() {
+			secret := func(token string) string {
+				return "apiVersion: v1\nkind: Secret\nmetadata:\n  name: s\ndata:\n  token: " + token
+			}
+
+			configMap := func(name string, lastKey string) string {
+				return "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + name + "\ndata:\n  key1: a\n  key2: b\n  key3: c\n  " + lastKey + ": d"
+			}
+
+			from := ytbx.InputFile{Documents: multiDoc(secret("abc"), configMap("cfg-a", "key4"))}
+			to := ytbx.InputFile{Documents: multiDoc(configMap("cfg-b", "key5"), secret("xyz"))}
+
+			report, err := dyff.CompareInputFiles(from, to, dyff.DetectRenames(true))
+			Expect(err).ToNot(HaveOccurred())
+
+			patches, err := report.JSONPatches()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(patches).To(HaveLen(2))
+
+			for idx, counterpart := range []int{1, 0} {
+				data, err := json.Marshal(patches[idx])
+				Expect(err).ToNot(HaveOccurred())
+
+				patched, err := dyff.ApplyJSONPatch(from.Documents[idx].Content[0], data)
+				Expect(err).ToNot(HaveOccurred())
+
+				result, err := dyff.CompareNodes(patched, to.Documents[counterpart].Content[0])
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Diffs).To(BeEmpty(), "patch %s", string(data))
+			}
+		})
+
+		It("should fail to create a patch for added, removed, or reordered documents", func() {
+			from := ytbx.InputFile{Documents: multiDoc(`{"a": 1}`, `{"b": 2}`)}
+			to := ytbx.InputFile{Documents: multiDoc(`{"a": 1}`, `{"b": 2}`, `{"c": 3}`)}
+
+			report, err := dyff.CompareInputFiles(from, to)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = report.JSONPatches()
+			Expect(err).To(MatchError(ContainSubstring("document-level changes")))
+
+			_, err = report.MergePatches()
+			Expect(err).To(MatchError(ContainSubstring("document-level changes")))
+
+			from = ytbx.InputFile{Documents: multiDoc(
+				`{"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "one"}}`,
+				`{"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "two"}}`,
+			)}
+
+			to = ytbx.InputFile{Documents: multiDoc(
+				`{"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "two"}}`,
+				`{"apiVersion": "v1", "kind": "Secret", "metadata": {"name": "one"}}`,
+			)}
+
+			report, err = dyff.CompareInputFiles(from, to)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = report.JSONPatches()
+			Expect(err).To(MatchError(ContainSubstring("document-level changes")))
+		})
+
+		It("should replace strings that contain embedded documents as a whole", func() {
+			operations := roundTrip(
+				`{"data": {"config.json": "{\"a\": 1, \"b\": {\"c\": 1, \"d\": 2}}"}}`,
+				`{"data": {"config.json": "{\"a\": 2, \"b\": {\"c\": 2, \"d\": 2}}"}}`,
+				dyff.DetectEmbeddedDocuments(true),
+			)
+
+			Expect(operations).To(HaveLen(1))
+			Expect(operations[0].Op).To(Equal("replace"))
+			Expect(operations[0].Path).To(Equal("/data/config.json"))
+			Expect(operations[0].Value.Value).To(Equal(`{"a": 2, "b": {"c": 2, "d": 2}}`))
+		})
+
+		It("should escape special characters in paths", func() {
+			operations := roundTrip(`{"a/b": {"c~d": 1}}`, `{"a/b": {"c~d": 2}}`)
+			Expect(operations).To(HaveLen(1))
+			Expect(operations[0].Op).To(Equal("replace"))
+			Expect(operations[0].Path).To(Equal("/a~1b/c~0d"))
+			Expect(operations[0].Value.Value).To(Equal("2"))
+		})
+	})
+
+	Context("creating merge patches", func() {
+		It("should create a merge patch that transforms from into to", func() {
+			from := yml(`{"name": "foo", "spec": {"replicas": 1, "paused": true, "ports": [80]}}`)
+			to := yml(`{"name": "foo", "spec": {"replicas": 2, "ports": [80, 443]}, "status": {}}`)
+
+			report, err := dyff.CompareNodes(from, to)
+			Expect(err).ToNot(HaveOccurred())
+
+			var buf bytes.Buffer
+			Expect((&dyff.MergePatchReport{Report: report}).WriteReport(&buf)).To(Succeed())
+			Expect(buf.String()).To(Equal(`{"spec":{"replicas":2,"paused":null,"ports":[80,443]},"status":{}}` + "\n"))
+
+			patched, err := dyff.ApplyMergePatch(from, buf.Bytes())
+			Expect(err).ToNot(HaveOccurred())
+
+			result, err := dyff.CompareNodes(patched, to)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Diffs).To(BeEmpty())
+		})
+
+		It("should write one patch per document", func() {
+			from := file(assets("kubernetes", "multi-docs", "from.yml"))
+			to := file(assets("kubernetes", "multi-docs", "to.yml"))
+
+			report, err := dyff.CompareInputFiles(from, to)
+			Expect(err).ToNot(HaveOccurred())
+
+			var buf bytes.Buffer
+			Expect((&dyff.JSONPatchReport{Report: report}).WriteReport(&buf)).To(Succeed())
+			Expect(strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")).To(HaveLen(len(from.Documents)))
+		})
+	})
+	Context("creating go-patch operations files", func() {
+		goPatch := func(from string, to string) string {
+			report, err := dyff.CompareNodes(yml(from), yml(to))
+			Expect(err).ToNot(HaveOccurred())
+
+			var buf bytes.Buffer
+			Expect((&dyff.GoPatchReport{Report: report}).WriteReport(&buf)).To(Succeed())
+			return buf.String()
+		}
+
+		It("should use name selectors for named entries and optional paths for additions", func() {
+			Expect(goPatch(`
+instance_groups:
+- name: api
+  instances: 2
+  jobs:
+  - name: cloud_controller
+    properties: {port: 9022}
+  - name: route_registrar
+- name: router
+  azs: [z1, z2]
+update:
+  canaries: 1
+`, `
+instance_groups:
+- name: api
+  instances: 3
+  jobs:
+  - name: cloud_controller
+    properties: {port: 9022, tls: true}
+- name: doppler
+  instances: 1
+- name: router
+  azs: [z1, z2, z3]
+update:
+  canaries: 1
+  max_in_flight: 2
+`)).To(Equal(`- type: replace
+  path: /instance_groups/name=router:before
+  value:
+    name: doppler
+    instances: 1
+- type: replace
+  path: /instance_groups/name=api/instances
+  value: 3
+- type: remove
+  path: /instance_groups/name=api/jobs/name=route_registrar
+- type: replace
+  path: /instance_groups/name=api/jobs/name=cloud_controller/properties/tls?
+  value: true
+- type: replace
+  path: /instance_groups/name=router/azs/-
+  value: z3
+- type: replace
+  path: /update/max_in_flight?
+  value: 2
+`))
+		})
+
+		It("should append new named entries using an optional name selector", func() {
+			Expect(goPatch(
+				`{"releases": [{"name": "a", "version": 1}]}`,
+				`{"releases": [{"name": "a", "version": 1}, {"name": "b/c", "version": 2}]}`,
+			)).To(Equal(`- type: replace
+  path: /releases/name=b~1c?
+  value: {"name": "b/c", "version": 2}
+`))
+		})
+
+		It("should write an empty operations file if there are no differences", func() {
+			Expect(goPatch(`{"a": 1}`, `{"a": 1}`)).To(Equal("[]\n"))
+		})
+
+		It("should use the index of list entries with a name that is not unique", func() {
+			Expect(goPatch(
+				`{"ports": [{"name": "http", "protocol": "TCP", "port": 80}, {"name": "http", "protocol": "UDP", "port": 80}, {"name": "dns", "protocol": "UDP", "port": 53}]}`,
+				`{"ports": [{"name": "http", "protocol": "TCP", "port": 80}, {"name": "dns", "protocol": "UDP", "port": 53}]}`,
+			)).To(Equal(`- type: remove
+  path: /ports/1
+`))
+		})
+
+		It("should replace strings that contain embedded documents as a whole", func() {
+			report, err := dyff.CompareNodes(
+				yml(`{"data": {"config.json": "{\"b\": {\"c\": 1}}"}}`),
+				yml(`{"data": {"config.json": "{\"b\": {\"c\": 2}}"}}`),
+				dyff.DetectEmbeddedDocuments(true),
+			)
+			Expect(err).ToNot(HaveOccurred())
+
+			var buf bytes.Buffer
+			Expect((&dyff.GoPatchReport{Report: report}).WriteReport(&buf)).To(Succeed())
+			Expect(buf.String()).To(Equal(`- type: replace
+  path: /data/config.json
+  value: "{\"b\": {\"c\": 2}}"
+`))
+		})
+	})
+	Context("applying go-patch operations files", func() {
+		apply := func(document string, patch string) (string, error) {
+			patched, err := dyff.ApplyGoPatch(singleDoc(document), []byte(patch))
+			if err != nil {
+				return "", err
+			}
+
+			out, err := yamlv3.Marshal(patched)
+			return string(out), err
+		}
+
+		It("should replace and remove entries using name selectors", func() {
+			Expect(apply(`
+jobs:
+- name: a
+  instances: 1
+- name: b
+`, `
+- type: replace
+  path: /jobs/name=a/instances
+  value: 2
+- type: remove
+  path: /jobs/name=b
+`)).To(Equal("jobs:\n    - name: a\n      instances: 2\n"))
+		})
+
+		It("should create missing entries for optional paths", func() {
+			Expect(apply(`{"jobs": []}`, `
+- type: replace
+  path: /jobs/name=a?/properties/port
+  value: 80
+- type: replace
+  path: /update?/canaries
+  value: 1
+- type: remove
+  path: /missing?/entry
+`)).To(Equal("{\"jobs\": [{name: a, properties: {port: 80}}], update: {canaries: 1}}\n"))
+		})
+
+		It("should insert entries before or after other entries and append entries", func() {
+			Expect(apply(`{"list": [b, d]}`, `
+- type: replace
+  path: /list/0:before
+  value: a
+- type: replace
+  path: /list/1:after
+  value: c
+- type: replace
+  path: /list/-
+  value: e
+`)).To(Equal("{\"list\": [a, b, c, d, e]}\n"))
+		})
+
+		It("should fail if a path does not exist", func() {
+			_, err := apply(`{"jobs": []}`, `[{"type": "replace", "path": "/jobs/name=a/instances", "value": 1}]`)
+			Expect(err).To(HaveOccurred())
+
+			_, err = apply(`{"jobs": []}`, `[{"type": "remove", "path": "/other"}]`)
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should round-trip the go-patch operations created from a report", func() {
+			from := yml(`
+instance_groups:
+- name: api
+  instances: 2
+  jobs: [{name: cc}, {name: uaa}]
+- name: router
+  azs: [z1, z2]
+`)
+			to := yml(`
+instance_groups:
+- name: router
+  azs: [z2, z3]
+- name: api
+  instances: 3
+  jobs: [{name: uaa}, {name: credhub}, {name: cc}]
+- name: doppler
+`)
+
+			report, err := dyff.CompareNodes(from, to)
+			Expect(err).ToNot(HaveOccurred())
+
+			var buf bytes.Buffer
+			Expect((&dyff.GoPatchReport{Report: report}).WriteReport(&buf)).To(Succeed())
+
+			patched, err := dyff.ApplyGoPatch(from, buf.Bytes())
+			Expect(err).ToNot(HaveOccurred())
+
+			result, err := dyff.CompareNodes(patched, to)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Diffs).To(BeEmpty(), "patch %s", buf.String())
+		})
+	})
+})
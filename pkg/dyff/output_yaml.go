@@ -23,7 +23,6 @@ type YAMLReportOutput struct {
 	Diffs      []YAMLReportDiff  `yaml:"diffs"`
 }
 
-// TODO: Support non-Kubernetes yaml documents
 func (report *YAMLReport) WriteReport(out io.Writer) error {
 	writer := bufio.NewWriter(out)
 	defer writer.Flush()
@@ -34,7 +33,9 @@ func (report *YAMLReport) WriteReport(out io.Writer) error {
 	for file, diffs := range consolidatedDiff {
 		meta, err := K8sMetaFromName(file)
 		if err != nil {
-			return err
+			// documents that are not Kubernetes resources are identified by
+			// their root description, e.g. `document #1`
+			meta = &K8sMetadata{Metadata: map[string]string{"name": file}}
 		}
 		var d []YAMLReportDiff
 		for _, diff := range diffs {
@@ -102,6 +103,10 @@ func (report *YAMLReport) consolidateDiff() (map[string][]YAMLReportDiff, error)
 				deet["to"] = outputTo
 				deet["from"] = outputFrom
 				deet["kind"] = "modification"
+				if diff.Details[0].Note != "" {
+					deet["note"] = diff.Details[0].Note
+				}
+
 			case ORDERCHANGE:
 				ytbx.RestructureObject(diff.Details[0].To)
 				outputTo, err := neat.NewOutputProcessor(false, true, nil).ToYAML(diff.Details[0].To)
@@ -113,6 +118,33 @@ func (report *YAMLReport) consolidateDiff() (map[string][]YAMLReportDiff, error)
 				deet["to"] = outputTo
 				deet["from"] = outputFrom
 				deet["kind"] = "orderchange"
+			case RENAME:
+				deet["to"] = diff.Details[0].To.Value
+				deet["from"] = diff.Details[0].From.Value
+				deet["kind"] = "rename"
+			case MOVE:
+				deet["to"] = diff.Path.String()
+				deet["from"] = diff.Details[0].Source.String()
+				deet["kind"] = "move"
+			case COMMENT:
+				deet["to"], deet["from"] = "", ""
+				if diff.Details[0].To != nil {
+					deet["to"] = diff.Details[0].To.Value
+				}
+				if diff.Details[0].From != nil {
+					deet["from"] = diff.Details[0].From.Value
+				}
+				deet["kind"] = "comment"
+				deet["note"] = diff.Details[0].Note
+			case ANCHOR:
+				deet["to"], deet["from"] = "", ""
+				if diff.Details[0].To != nil {
+					deet["to"] = diff.Details[0].To.Value
+				}
+				if diff.Details[0].From != nil {
+					deet["from"] = diff.Details[0].From.Value
+				}
+				deet["kind"] = "anchor"
 			}
 		case 2:
 			for _, detail := range diff.Details {
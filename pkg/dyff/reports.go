@@ -171,6 +171,21 @@ func (r Report) ExcludeDocumentRegexp(pattern ...string) (result Report) {
 	})
 }
 
+// HasChanges returns whether the report contains at least one actual change,
+// scalars that only have an equivalent value (for example `1.0` and `1`) are
+// reported, but are no changes
+func (r Report) HasChanges() bool {
+	for _, diff := range r.Diffs {
+		for _, detail := range diff.Details {
+			if !isEquivalentValue(detail) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func (r Report) IgnoreValueChanges() (result Report) {
 	result = Report{
 		From: r.From,